@@ -0,0 +1,5 @@
+# Audit the GPG keys registered for a set of namespaces.
+data "tfepatch_gpg_keys" "this" {
+  namespaces    = [var.organization_name]
+  key_id_prefix = "5C3A"
+}
@@ -0,0 +1,7 @@
+# Look up a registry provider managed elsewhere.
+data "tfepatch_registry_provider" "this" {
+  organization  = var.organization_name
+  namespace     = var.organization_name
+  name          = "demo-provider"
+  registry_name = "private"
+}
@@ -1,2 +1,2 @@
-#Import by synthetic key '<namespace>||<key_id>'
-terraform import tfepatch_gpg_key.this '<namespace>||<key_id>'
\ No newline at end of file
+# Import by '<organization>/<namespace>/<key_id>'. The former '<namespace>||<key_id>' id is still accepted
+terraform import tfepatch_gpg_key.this 'my-org-name/my-org-name/32966F3FB5AC1129'
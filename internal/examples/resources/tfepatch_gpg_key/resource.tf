@@ -21,3 +21,10 @@ resource "tfepatch_gpg_key" "this" {
   namespace    = "gruntwork-corp"
   public_key   = gpg_private_key.this.public_key
 }
+
+# Rotate the key in place: the previous key stays registered until no provider version is signed with it
+resource "tfepatch_gpg_key" "rotating" {
+  namespace       = "gruntwork-corp"
+  public_key      = gpg_private_key.this.public_key
+  retain_previous = true
+}
@@ -1,2 +1,3 @@
-# Import by synthetic key '<namespace>||<name>||<registry_name>'
-terraform import tfepatch_registry_provider.example 'my-org-name||tfepatch||private'
\ No newline at end of file
+# Import by '<organization>/<registry_name>/<namespace>/<name>'. The former '<namespace>||<name>||<registry_name>' id is still accepted
+# The id of the imported provider is '<namespace>||<name>||<registry_name>', which differs from the import id
+terraform import tfepatch_registry_provider.example 'my-org-name/private/my-org-name/tfepatch'
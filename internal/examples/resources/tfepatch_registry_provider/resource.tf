@@ -5,3 +5,19 @@ resource "tfepatch_registry_provider" "this" {
   name          = "aws"
   registry_name = "public"
 }
+
+# Private providers cannot be deleted while they have published versions, unless force_destroy has been applied first.
+resource "tfepatch_registry_provider" "private" {
+  namespace     = var.organization_name
+  name          = "tfepatch"
+  registry_name = "private"
+  force_destroy = false
+}
+
+# Take a provider that was created outside of Terraform into state instead of importing it.
+resource "tfepatch_registry_provider" "existing" {
+  namespace      = var.organization_name
+  name           = "legacy"
+  registry_name  = "private"
+  adopt_existing = true
+}
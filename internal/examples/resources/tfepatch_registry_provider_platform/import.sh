@@ -0,0 +1,2 @@
+# Import by '<organization>/<namespace>/<name>/<version>/<os>/<arch>'
+terraform import tfepatch_registry_provider_platform.linux_amd64 'my-org-name/my-org-name/tfepatch/0.1.0/linux/amd64'
@@ -0,0 +1,10 @@
+# Register a platform under a provider version and upload the provider binary archive.
+resource "tfepatch_registry_provider_platform" "linux_amd64" {
+  organization = tfepatch_registry_provider_version.this.organization
+  namespace    = tfepatch_registry_provider_version.this.namespace
+  name         = tfepatch_registry_provider_version.this.name
+  version      = tfepatch_registry_provider_version.this.version
+  os           = "linux"
+  arch         = "amd64"
+  archive_path = "${path.module}/dist/terraform-provider-tfepatch_0.1.0_linux_amd64.zip"
+}
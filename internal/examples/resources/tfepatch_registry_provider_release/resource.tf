@@ -0,0 +1,14 @@
+# Publish every archive of a goreleaser release (run with the checksum and signs sections enabled).
+resource "tfepatch_registry_provider_release" "this" {
+  organization = tfepatch_registry_provider.this.organization
+  namespace    = tfepatch_registry_provider.this.namespace
+  name         = tfepatch_registry_provider.this.name
+  key_id       = tfepatch_gpg_key.this.key_id
+  protocols    = ["6.0"]
+  dist_dir     = "${path.module}/../internal/dist"
+
+  # Uploading every archive of a large release can take a while
+  timeouts {
+    create = "45m"
+  }
+}
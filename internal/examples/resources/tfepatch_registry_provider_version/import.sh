@@ -0,0 +1,2 @@
+# Import by '<organization>/<namespace>/<name>/<version>'
+terraform import tfepatch_registry_provider_version.this 'my-org-name/my-org-name/tfepatch/0.1.0'
@@ -0,0 +1,11 @@
+# Create a provider version and upload the signed SHA256SUMS produced by goreleaser.
+resource "tfepatch_registry_provider_version" "this" {
+  organization     = tfepatch_registry_provider.this.organization
+  namespace        = tfepatch_registry_provider.this.namespace
+  name             = tfepatch_registry_provider.this.name
+  version          = "0.1.0"
+  key_id           = tfepatch_gpg_key.this.key_id
+  protocols        = ["6.0"]
+  shasums_path     = "${path.module}/dist/terraform-provider-tfepatch_0.1.0_SHA256SUMS"
+  shasums_sig_path = "${path.module}/dist/terraform-provider-tfepatch_0.1.0_SHA256SUMS.sig"
+}
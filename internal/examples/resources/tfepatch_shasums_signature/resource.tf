@@ -0,0 +1,19 @@
+# Sign the SHA256SUMS produced by goreleaser with the private key of the registered GPG key.
+resource "tfepatch_shasums_signature" "this" {
+  key_id         = tfepatch_gpg_key.this.key_id
+  private_key    = gpg_private_key.this.private_key
+  passphrase     = gpg_private_key.this.passphrase
+  shasums_path   = "${path.module}/dist/terraform-provider-tfepatch_0.1.0_SHA256SUMS"
+  signature_path = "${path.module}/dist/terraform-provider-tfepatch_0.1.0_SHA256SUMS.sig"
+}
+
+resource "tfepatch_registry_provider_version" "this" {
+  organization     = tfepatch_registry_provider.this.organization
+  namespace        = tfepatch_registry_provider.this.namespace
+  name             = tfepatch_registry_provider.this.name
+  version          = "0.1.0"
+  key_id           = tfepatch_shasums_signature.this.key_id
+  protocols        = ["6.0"]
+  shasums_path     = tfepatch_shasums_signature.this.shasums_path
+  shasums_sig_path = tfepatch_shasums_signature.this.signature_path
+}
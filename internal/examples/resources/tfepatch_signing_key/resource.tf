@@ -0,0 +1,16 @@
+# Generate a signing key valid for two years and register its public key
+resource "tfepatch_signing_key" "this" {
+  namespace   = "gruntwork-corp"
+  name        = "Gruntwork"
+  email       = "donotreply@gruntwork.com"
+  expiry_days = 730
+}
+
+# Sign releases with the generated key
+resource "tfepatch_shasums_signature" "this" {
+  key_id         = tfepatch_signing_key.this.key_id
+  private_key    = tfepatch_signing_key.this.private_key
+  passphrase     = tfepatch_signing_key.this.passphrase
+  shasums_path   = "${path.module}/dist/terraform-provider-tfepatch_0.1.0_SHA256SUMS"
+  signature_path = "${path.module}/dist/terraform-provider-tfepatch_0.1.0_SHA256SUMS.sig"
+}
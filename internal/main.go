@@ -2,7 +2,7 @@ package main
 
 import (
 	"context"
-	"fmt"
+	"flag"
 	"log"
 
 	"github.com/tsanton/terraform-provider-tfepatch/provider"
@@ -13,13 +13,19 @@ import (
 // Generate the Terraform provider documentation using `tfplugindocs`:
 //go:generate go run github.com/hashicorp/terraform-plugin-docs/cmd/tfplugindocs generate --provider-name tfepatch
 
+// address is the registry address the provider is published under, '<hostname>/<namespace>/tfepatch'.
+// Builds for a private registry set it with -ldflags "-X main.address=app.terraform.io/<organization>/tfepatch".
+var address = "bootstrap/automation/tfepatch"
+
 func main() {
-	providerHostname := "tsanton"
-	namespace := "gruntwork-corp"
-	providerName := "tfepatch"
+	var debug bool
+	flag.BoolVar(&debug, "debug", false, "Start the provider in debug mode for debuggers such as delve, printing the TF_REATTACH_PROVIDERS value to run Terraform with")
+	flag.StringVar(&address, "address", address, "The registry address the provider is published under, '<hostname>/<namespace>/<name>'")
+	flag.Parse()
+
 	opts := providerserver.ServeOpts{
-		Address: fmt.Sprintf("%s/%s/%s", providerHostname, namespace, providerName),
-		Debug:   false,
+		Address: address,
+		Debug:   debug,
 	}
 
 	err := providerserver.Serve(context.Background(), provider.New(), opts)
@@ -0,0 +1,352 @@
+package provider_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	// cassetteModeEnv selects whether the Test_provider_* tests run live (unset), record their TFE exchanges or replay them
+	cassetteModeEnv    = "TFE_CASSETTE_MODE"
+	cassetteModeRecord = "record"
+	cassetteModeReplay = "replay"
+	// cassetteOrganization and cassetteToken replace the organization name and token in cassettes
+	cassetteOrganization = "tfepatch-cassette-org"
+	cassetteToken        = "cassette-token"
+	cassetteDir          = "testdata/cassettes"
+	// cassetteProxyUrl stands in for the url of the proxy in recorded responses
+	cassetteProxyUrl = "{{cassette}}"
+	// cassetteOriginPath prefixes the paths the proxy forwards to other origins than the TFE API, such as upload links
+	cassetteOriginPath = "/_origin/"
+)
+
+// absoluteUrlPattern matches the origin of absolute urls in response bodies
+var absoluteUrlPattern = regexp.MustCompile(`https?://[a-zA-Z0-9.:-]+`)
+
+// cassettes proxies the Test_provider_* tests in record and replay mode, nil when they run live.
+var cassettes *cassetteProxy
+
+// cassetteInteraction is a single recorded request and its response.
+type cassetteInteraction struct {
+	Method      string `json:"method"`
+	Url         string `json:"url"`
+	RequestBody string `json:"request_body,omitempty"`
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        string `json:"body,omitempty"`
+}
+
+type cassette struct {
+	Interactions []cassetteInteraction `json:"interactions"`
+}
+
+// cassetteProxy stands in for the TFE API: in record mode it forwards requests upstream and records the exchanges,
+// in replay mode it serves the recorded responses for each method and url in the order they were recorded.
+// Urls of other origins in responses, such as upload links, are rewritten to go through the proxy as well.
+type cassetteProxy struct {
+	server   *httptest.Server
+	mode     string
+	upstream *url.URL
+	// token and organization are scrubbed from recorded exchanges
+	token        string
+	organization string
+
+	mu        sync.Mutex
+	path      string
+	recorded  []cassetteInteraction
+	replaying map[string][]cassetteInteraction
+	replayed  map[string]int
+}
+
+func newCassetteProxy(mode, upstream, token, organization string) (*cassetteProxy, error) {
+	if mode != cassetteModeRecord && mode != cassetteModeReplay {
+		return nil, fmt.Errorf("%s must be %q or %q, got %q", cassetteModeEnv, cassetteModeRecord, cassetteModeReplay, mode)
+	}
+	upstreamUrl, err := url.Parse(upstream)
+	if err != nil {
+		return nil, err
+	}
+	proxy := &cassetteProxy{mode: mode, upstream: upstreamUrl, token: token, organization: organization}
+	proxy.server = httptest.NewServer(http.HandlerFunc(proxy.serveHTTP))
+	return proxy, nil
+}
+
+// useCassette routes the TFE exchanges of the test through the cassette of the test when recording or replaying.
+// Replaying skips tests without a cassette.
+func useCassette(t *testing.T) {
+	if cassettes == nil {
+		return
+	}
+	path := filepath.Join(cassetteDir, strings.ReplaceAll(t.Name(), "/", "_")+".json")
+	if cassettes.mode == cassetteModeReplay {
+		if err := cassettes.load(path); os.IsNotExist(err) {
+			t.Skipf("no cassette %s to replay", path)
+		} else if err != nil {
+			t.Fatalf("unable to load cassette %s: %s", path, err)
+		}
+		requireTerraformCli(t)
+		return
+	}
+	cassettes.start(path)
+	t.Cleanup(func() {
+		if err := cassettes.save(); err != nil {
+			t.Errorf("unable to save cassette %s: %s", path, err)
+		}
+	})
+}
+
+func (p *cassetteProxy) start(path string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.path = path
+	p.recorded = nil
+}
+
+func (p *cassetteProxy) load(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var c cassette
+	if err := json.Unmarshal(content, &c); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.path = path
+	p.replaying = map[string][]cassetteInteraction{}
+	p.replayed = map[string]int{}
+	for _, interaction := range c.Interactions {
+		key := interaction.Method + " " + interaction.Url
+		p.replaying[key] = append(p.replaying[key], interaction)
+	}
+	return nil
+}
+
+func (p *cassetteProxy) save() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	content, err := json.MarshalIndent(cassette{Interactions: p.recorded}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(p.path, append(content, '\n'), 0o644)
+}
+
+func (p *cassetteProxy) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var interaction cassetteInteraction
+	if p.mode == cassetteModeReplay {
+		var ok bool
+		if interaction, ok = p.replay(r.Method, r.URL.RequestURI()); !ok {
+			http.Error(w, fmt.Sprintf("no recorded response for %s %s", r.Method, r.URL.RequestURI()), http.StatusNotImplemented)
+			return
+		}
+	} else {
+		if interaction, err = p.record(r, requestBody); err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+	}
+
+	if interaction.ContentType != "" {
+		w.Header().Set("Content-Type", interaction.ContentType)
+	}
+	body := strings.ReplaceAll(interaction.Body, cassetteProxyUrl, p.server.URL)
+	w.Header().Set("Content-Length", fmt.Sprint(len(body)))
+	w.WriteHeader(interaction.Status)
+	_, _ = io.WriteString(w, body)
+}
+
+// replay returns the next recorded response for the request, repeating the last one once they are exhausted.
+func (p *cassetteProxy) replay(method, requestUri string) (cassetteInteraction, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key := method + " " + requestUri
+	interactions := p.replaying[key]
+	if len(interactions) == 0 {
+		return cassetteInteraction{}, false
+	}
+	i := p.replayed[key]
+	if i >= len(interactions) {
+		i = len(interactions) - 1
+	}
+	p.replayed[key] = i + 1
+	return interactions[i], true
+}
+
+// record forwards the request upstream and records the scrubbed exchange, returning the unscrubbed response.
+// The token is only forwarded to the TFE API, never to other origins.
+func (p *cassetteProxy) record(r *http.Request, requestBody []byte) (cassetteInteraction, error) {
+	headers := []string{"Content-Type", "Accept"}
+	target := p.upstream.ResolveReference(&url.URL{Path: r.URL.Path, RawQuery: r.URL.RawQuery})
+	if strings.HasPrefix(r.URL.Path, cassetteOriginPath) {
+		scheme, rest, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, cassetteOriginPath), "/")
+		host, rest, _ := strings.Cut(rest, "/")
+		target = &url.URL{Scheme: scheme, Host: host, Path: "/" + rest, RawQuery: r.URL.RawQuery}
+	} else {
+		headers = append(headers, "Authorization")
+	}
+	req, err := http.NewRequestWithContext(r.Context(), r.Method, target.String(), bytes.NewReader(requestBody))
+	if err != nil {
+		return cassetteInteraction{}, err
+	}
+	for _, header := range headers {
+		if value := r.Header.Get(header); value != "" {
+			req.Header.Set(header, value)
+		}
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return cassetteInteraction{}, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return cassetteInteraction{}, err
+	}
+
+	interaction := cassetteInteraction{
+		Method:      r.Method,
+		Url:         r.URL.RequestURI(),
+		RequestBody: string(requestBody),
+		Status:      resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        p.rewriteOrigins(string(body)),
+	}
+	scrubbed := interaction
+	scrubbed.Url = p.scrub(scrubbed.Url)
+	scrubbed.RequestBody = p.scrub(scrubbed.RequestBody)
+	scrubbed.Body = p.scrub(scrubbed.Body)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.recorded = append(p.recorded, scrubbed)
+	return interaction, nil
+}
+
+// rewriteOrigins points the absolute urls of other origins than the TFE API at the proxy.
+func (p *cassetteProxy) rewriteOrigins(body string) string {
+	return absoluteUrlPattern.ReplaceAllStringFunc(body, func(origin string) string {
+		originUrl, err := url.Parse(origin)
+		if err != nil || originUrl.Host == p.upstream.Host {
+			return origin
+		}
+		return cassetteProxyUrl + cassetteOriginPath + originUrl.Scheme + "/" + originUrl.Host
+	})
+}
+
+// scrub replaces the token and organization name, in any case, with their cassette placeholders.
+func (p *cassetteProxy) scrub(value string) string {
+	if p.token != "" {
+		value = strings.ReplaceAll(value, p.token, cassetteToken)
+	}
+	if p.organization != "" {
+		for _, organization := range []string{p.organization, strings.ToLower(p.organization), url.PathEscape(p.organization)} {
+			value = strings.ReplaceAll(value, organization, cassetteOrganization)
+		}
+	}
+	return value
+}
+
+// loadCassetteGpgEntity returns the committed GPG test key, so that the requests of recorded tests are the same on replay.
+func loadCassetteGpgEntity(t *testing.T) *openpgp.Entity {
+	f, err := os.Open("testdata/gpg_test_key.asc")
+	if err != nil {
+		t.Fatalf("unable to open the GPG test key: %s", err)
+	}
+	defer f.Close()
+	entities, err := openpgp.ReadArmoredKeyRing(f)
+	if err != nil || len(entities) != 1 {
+		t.Fatalf("unable to read the GPG test key: %v", err)
+	}
+	return entities[0]
+}
+
+func Test_cassette_proxy_records_and_replays(t *testing.T) {
+	/* Arrange */
+	fake := newFakeTfeApi(t)
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	recorder, err := newCassetteProxy(cassetteModeRecord, fake.server.URL, fakeToken, fakeOrganization)
+	assert.Nil(t, err)
+	t.Cleanup(recorder.server.Close)
+	recorder.start(path)
+	body := `{"data": {"type": "registry-providers", "attributes": {"name": "demo-provider", "namespace": "test-org", "registry-name": "private"}}}`
+
+	/* Act */
+	created := cassetteRequest(t, http.MethodPost, recorder.server.URL+"/api/v2/organizations/test-org/registry-providers", fakeToken, body)
+	recorded := cassetteRequest(t, http.MethodGet, recorder.server.URL+"/api/v2/organizations/test-org/registry-providers/private/test-org/demo-provider", fakeToken, "")
+	assert.Nil(t, recorder.save())
+
+	replayer, err := newCassetteProxy(cassetteModeReplay, fake.server.URL, "", "")
+	assert.Nil(t, err)
+	t.Cleanup(replayer.server.Close)
+	assert.Nil(t, replayer.load(path))
+	replayed := cassetteRequest(t, http.MethodGet, replayer.server.URL+"/api/v2/organizations/"+cassetteOrganization+"/registry-providers/private/"+cassetteOrganization+"/demo-provider", cassetteToken, "")
+	unrecorded := cassetteRequest(t, http.MethodGet, replayer.server.URL+"/api/v2/organizations/"+cassetteOrganization+"/registry-providers/private/"+cassetteOrganization+"/other-provider", cassetteToken, "")
+
+	/* Assert */
+	assert.Equal(t, http.StatusCreated, created.StatusCode)
+	assert.Equal(t, http.StatusOK, recorded.StatusCode)
+	content, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(content), fakeOrganization)
+	assert.NotContains(t, string(content), fakeToken)
+	assert.Contains(t, string(content), cassetteOrganization)
+	assert.Equal(t, http.StatusOK, replayed.StatusCode)
+	assert.Equal(t, strings.ReplaceAll(recorded.body, fakeOrganization, cassetteOrganization), replayed.body)
+	assert.Equal(t, http.StatusNotImplemented, unrecorded.StatusCode)
+}
+
+type cassetteResponse struct {
+	StatusCode int
+	body       string
+}
+
+func cassetteRequest(t *testing.T, method, url, token, body string) cassetteResponse {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	assert.Nil(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/vnd.api+json")
+	resp, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+	content, err := io.ReadAll(resp.Body)
+	assert.Nil(t, err)
+	return cassetteResponse{StatusCode: resp.StatusCode, body: string(content)}
+}
+
+func Test_cassette_proxy_rewrites_other_origins(t *testing.T) {
+	/* Arrange */
+	proxy := &cassetteProxy{upstream: &url.URL{Scheme: "https", Host: "app.terraform.io"}}
+	body := `{"links": {"self": "https://app.terraform.io/api/v2/x", "shasums-upload": "https://archivist.terraform.io/v1/object/abc"}}`
+
+	/* Act */
+	rewritten := proxy.rewriteOrigins(body)
+
+	/* Assert */
+	assert.Equal(t, `{"links": {"self": "https://app.terraform.io/api/v2/x", "shasums-upload": "{{cassette}}/_origin/https/archivist.terraform.io/v1/object/abc"}}`, rewritten)
+}
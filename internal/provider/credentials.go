@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const defaultHostname = "app.terraform.io"
+
+// hostAddress returns the API address for a configured hostname, defaulting the scheme to https.
+func hostAddress(hostname string) (*url.URL, error) {
+	if !strings.Contains(hostname, "://") {
+		hostname = "https://" + hostname
+	}
+	address, err := url.Parse(hostname)
+	if err != nil {
+		return nil, err
+	}
+	if address.Host == "" {
+		return nil, fmt.Errorf("no host in hostname %s", hostname)
+	}
+	return address, nil
+}
+
+// cliCredentialsToken looks up the token the Terraform CLI uses for host.
+// In order: the TF_TOKEN_<host> environment variable, credentials.tfrc.json (written by `terraform login`) and the credentials blocks of the CLI config file.
+func cliCredentialsToken(host string) (string, error) {
+	if token := os.Getenv(credentialsEnvName(host)); token != "" {
+		return token, nil
+	}
+
+	configDir, err := cliConfigDir()
+	if err != nil {
+		return "", err
+	}
+	token, err := credentialsFileToken(filepath.Join(configDir, "credentials.tfrc.json"), host)
+	if err != nil || token != "" {
+		return token, err
+	}
+
+	configFile := os.Getenv("TF_CLI_CONFIG_FILE")
+	if configFile == "" {
+		configFile, err = cliConfigFile()
+		if err != nil {
+			return "", err
+		}
+	}
+	return cliConfigFileToken(configFile, host)
+}
+
+// credentialsEnvName returns the TF_TOKEN_ variable name for host: periods are encoded as underscores and hyphens as double underscores.
+func credentialsEnvName(host string) string {
+	return "TF_TOKEN_" + strings.NewReplacer(".", "_", "-", "__").Replace(host)
+}
+
+// credentialsFileToken reads the token for host from a credentials.tfrc.json file. A missing file holds no credentials.
+func credentialsFileToken(path, host string) (string, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to read %s: %w", path, err)
+	}
+
+	var credentials struct {
+		Credentials map[string]struct {
+			Token string `json:"token"`
+		} `json:"credentials"`
+	}
+	if err = json.Unmarshal(content, &credentials); err != nil {
+		return "", fmt.Errorf("unable to parse %s: %w", path, err)
+	}
+	return credentials.Credentials[host].Token, nil
+}
+
+// cliConfigFileToken reads the token of the `credentials "<host>"` block of a CLI config file. A missing file holds no credentials.
+func cliConfigFileToken(path, host string) (string, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to read %s: %w", path, err)
+	}
+
+	file, diags := hclsyntax.ParseConfig(content, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return "", fmt.Errorf("unable to parse %s: %s", path, diags.Error())
+	}
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return "", nil
+	}
+	for _, block := range body.Blocks {
+		if block.Type != "credentials" || len(block.Labels) != 1 || block.Labels[0] != host {
+			continue
+		}
+		attribute, ok := block.Body.Attributes["token"]
+		if !ok {
+			continue
+		}
+		value, diags := attribute.Expr.Value(nil)
+		if diags.HasErrors() || value.IsNull() || !value.Type().Equals(cty.String) {
+			return "", fmt.Errorf("the token of the credentials block for %s in %s must be a string", host, path)
+		}
+		return value.AsString(), nil
+	}
+	return "", nil
+}
+
+// cliConfigDir returns the directory holding credentials.tfrc.json.
+func cliConfigDir() (string, error) {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("APPDATA"), "terraform.d"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".terraform.d"), nil
+}
+
+// cliConfigFile returns the default location of the CLI config file.
+func cliConfigFile() (string, error) {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("APPDATA"), "terraform.rc"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".terraformrc"), nil
+}
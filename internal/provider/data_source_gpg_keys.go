@@ -0,0 +1,161 @@
+package provider
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	m "github.com/tsanton/terraform-provider-tfepatch/provider/models"
+	api "github.com/tsanton/tfe-client/tfe"
+)
+
+type GpgKeysDataSource struct {
+	client *api.TerraformEnterpriseClient
+}
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &GpgKeysDataSource{}
+	_ datasource.DataSourceWithConfigure = &GpgKeysDataSource{}
+)
+
+// newDataSource is a helper function to simplify the provider implementation.
+func newGpgKeysDataSource() datasource.DataSource {
+	return &GpgKeysDataSource{}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *GpgKeysDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	d.client = req.ProviderData.(*providerData).client
+}
+
+func (d *GpgKeysDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				Description:         "The sorted, comma separated namespaces",
+				MarkdownDescription: "The sorted, comma separated namespaces",
+			},
+			"keys": schema.ListNestedAttribute{
+				Computed:            true,
+				Description:         "The GPG keys of the namespaces, ordered by namespace and key_id",
+				MarkdownDescription: "The GPG keys of the namespaces, ordered by namespace and `key_id`",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"namespace": schema.StringAttribute{
+							Computed:            true,
+							Description:         "The namespace the key is registered in",
+							MarkdownDescription: "The namespace the key is registered in",
+						},
+						"key_id": schema.StringAttribute{
+							Computed:            true,
+							Description:         "The identity of the key",
+							MarkdownDescription: "The identity of the key",
+						},
+						"ascii_armor": schema.StringAttribute{
+							Computed:            true,
+							Description:         "The ASCII-armored public key",
+							MarkdownDescription: "The ASCII-armored public key",
+						},
+						"source": schema.StringAttribute{
+							Computed:            true,
+							Description:         "The origin of the key",
+							MarkdownDescription: "The origin of the key",
+						},
+						"created_at": schema.StringAttribute{
+							Computed:            true,
+							Description:         "The RFC3339 timestamp of when the key was registered",
+							MarkdownDescription: "The RFC3339 timestamp of when the key was registered",
+						},
+						"updated_at": schema.StringAttribute{
+							Computed:            true,
+							Description:         "The RFC3339 timestamp of when the key was last updated",
+							MarkdownDescription: "The RFC3339 timestamp of when the key was last updated",
+						},
+					},
+				},
+			},
+			// Input attributes
+			"namespaces": schema.ListAttribute{
+				Required:            true,
+				ElementType:         types.StringType,
+				Description:         "The namespaces to list the GPG keys of",
+				MarkdownDescription: "The namespaces to list the GPG keys of",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"key_id_prefix": schema.StringAttribute{
+				Optional:            true,
+				Description:         "Only return keys whose key_id starts with the prefix, ignoring case",
+				MarkdownDescription: "Only return keys whose `key_id` starts with the prefix, ignoring case",
+			},
+		},
+	}
+}
+
+// Metadata returns the data source type name.
+func (d *GpgKeysDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_gpg_keys"
+}
+
+func (d *GpgKeysDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = recordApiErrors(ctx)
+	var state m.GpgKeysDataSource
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespaces := []string{}
+	for _, namespace := range state.Namespaces {
+		namespaces = append(namespaces, namespace.ValueString())
+	}
+	sort.Strings(namespaces)
+
+	keys, err := listGpgKeys(ctx, d.client, namespaces)
+	if err != nil {
+		addApiError(ctx, &resp.Diagnostics, "list the GPG keys of namespaces "+strings.Join(namespaces, ", "), err)
+		return
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Attributes.Namespace != keys[j].Attributes.Namespace {
+			return keys[i].Attributes.Namespace < keys[j].Attributes.Namespace
+		}
+		return keys[i].Attributes.KeyId < keys[j].Attributes.KeyId
+	})
+
+	prefix := strings.ToUpper(state.KeyIdPrefix.ValueString())
+	state.Id = types.StringValue(strings.Join(namespaces, ","))
+	state.Keys = []m.GpgKeysDataSourceKey{}
+	for _, key := range keys {
+		if !strings.HasPrefix(strings.ToUpper(key.Attributes.KeyId), prefix) {
+			continue
+		}
+		state.Keys = append(state.Keys, m.GpgKeysDataSourceKey{
+			Namespace:  types.StringValue(key.Attributes.Namespace),
+			KeyId:      types.StringValue(key.Attributes.KeyId),
+			AsciiArmor: types.StringValue(key.Attributes.AsciiArmor),
+			Source:     types.StringValue(key.Attributes.Source),
+			CreatedAt:  types.StringValue(key.Attributes.CreatedAt.Format(time.RFC3339)),
+			UpdatedAt:  types.StringValue(key.Attributes.UpdatedAt.Format(time.RFC3339)),
+		})
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
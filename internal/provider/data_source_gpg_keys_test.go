@@ -0,0 +1,101 @@
+package provider_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/assert"
+	m "github.com/tsanton/terraform-provider-tfepatch/provider/models"
+	u "github.com/tsanton/terraform-provider-tfepatch/utilities"
+)
+
+func Test_provider_gpg_keys_data_source(t *testing.T) {
+	useCassette(t)
+	/* Arrange */
+	orgName := u.GetEnv("TFE_ORG_NAME", "")
+	entity := loadCassetteGpgEntity(t)
+	publicKey, err := generateGpgKey(entity)
+	assert.Nil(t, err)
+
+	/* Act */
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			//--------------------------------------------------------------------------
+			//--- Read testing
+			//--------------------------------------------------------------------------
+			{
+				Config: providerConfig + fmt.Sprintf(`
+				resource "tfepatch_gpg_key" "this" {
+					organization = "%[1]s"
+					namespace    = "%[1]s"
+					public_key   = trimspace(<<EOF
+%[2]s
+EOF
+)
+				}
+
+				data "tfepatch_gpg_keys" "this" {
+					namespaces    = [tfepatch_gpg_key.this.namespace]
+					key_id_prefix = tfepatch_gpg_key.this.key_id
+				}
+				`, orgName, publicKey),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tfepatch_gpg_keys.this", "id", orgName),
+					resource.TestCheckResourceAttr("data.tfepatch_gpg_keys.this", "keys.#", "1"),
+					resource.TestCheckResourceAttrPair("data.tfepatch_gpg_keys.this", "keys.0.key_id", "tfepatch_gpg_key.this", "key_id"),
+					resource.TestCheckResourceAttrSet("data.tfepatch_gpg_keys.this", "keys.0.ascii_armor"),
+				),
+			},
+		},
+	})
+}
+
+func Test_gpg_keys_data_source_read(t *testing.T) {
+	/* Arrange */
+	var filter string
+	server := newTestApiServer(t, func(w http.ResponseWriter, r *http.Request) {
+		filter = r.URL.Query().Get("filter[namespace]")
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		if r.URL.Query().Get("page[number]") == "1" {
+			_, _ = w.Write([]byte(`{"data": [
+				{"id": "1", "attributes": {"namespace": "team-b", "key-id": "5C3A4E1F2B9D8E7A", "ascii-armor": "key-1", "source": "TerraformCloud",
+					"created-at": "2023-01-02T03:04:05Z", "updated-at": "2023-01-02T03:04:05Z"}},
+				{"id": "2", "attributes": {"namespace": "team-a", "key-id": "5C3A000000000000", "ascii-armor": "key-2", "source": "TerraformCloud",
+					"created-at": "2023-01-02T03:04:05Z", "updated-at": "2023-02-03T04:05:06Z"}}],
+				"meta": {"pagination": {"current-page": 1, "next-page": 2, "total-pages": 2}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": [
+			{"id": "3", "attributes": {"namespace": "team-a", "key-id": "A1B2C3D4E5F60718", "ascii-armor": "key-3", "source": "TerraformCloud",
+				"created-at": "2023-01-02T03:04:05Z", "updated-at": "2023-01-02T03:04:05Z"}}],
+			"meta": {"pagination": {"current-page": 2, "next-page": null, "total-pages": 2}}}`))
+	})
+	d := newTestDataSource(t, "tfepatch_gpg_keys", testProviderValues(server.URL))
+
+	/* Act */
+	resp := readTestDataSource(d, map[string]tftypes.Value{
+		"namespaces": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+			tftypes.NewValue(tftypes.String, "team-b"),
+			tftypes.NewValue(tftypes.String, "team-a"),
+		}),
+		"key_id_prefix": tftypes.NewValue(tftypes.String, "5c3a"),
+	})
+
+	/* Assert */
+	assert.False(t, resp.Diagnostics.HasError())
+	assert.Equal(t, "team-a,team-b", filter)
+	var state m.GpgKeysDataSource
+	resp.State.Get(context.Background(), &state)
+	assert.Equal(t, "team-a,team-b", state.Id.ValueString())
+	assert.Len(t, state.Keys, 2)
+	assert.Equal(t, "5C3A000000000000", state.Keys[0].KeyId.ValueString())
+	assert.Equal(t, "team-a", state.Keys[0].Namespace.ValueString())
+	assert.Equal(t, "2023-02-03T04:05:06Z", state.Keys[0].UpdatedAt.ValueString())
+	assert.Equal(t, "5C3A4E1F2B9D8E7A", state.Keys[1].KeyId.ValueString())
+	assert.Equal(t, "key-1", state.Keys[1].AsciiArmor.ValueString())
+}
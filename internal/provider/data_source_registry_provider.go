@@ -0,0 +1,207 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	m "github.com/tsanton/terraform-provider-tfepatch/provider/models"
+	api "github.com/tsanton/tfe-client/tfe"
+	"github.com/tsanton/tfe-client/tfe/models/enum"
+)
+
+type RegistryProviderDataSource struct {
+	client       *api.TerraformEnterpriseClient
+	organization string
+}
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &RegistryProviderDataSource{}
+	_ datasource.DataSourceWithConfigure = &RegistryProviderDataSource{}
+)
+
+// newDataSource is a helper function to simplify the provider implementation.
+func newRegistryProviderDataSource() datasource.DataSource {
+	return &RegistryProviderDataSource{}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *RegistryProviderDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	data := req.ProviderData.(*providerData)
+	d.client = data.client
+	d.organization = data.organization
+}
+
+func (d *RegistryProviderDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				Description:         "The TFE id of the provider",
+				MarkdownDescription: "The TFE id of the provider",
+			},
+			"created_at": schema.StringAttribute{
+				Computed:            true,
+				Description:         "The RFC3339 timestamp of when the provider was created",
+				MarkdownDescription: "The RFC3339 timestamp of when the provider was created",
+			},
+			"updated_at": schema.StringAttribute{
+				Computed:            true,
+				Description:         "The RFC3339 timestamp of when the provider was last updated",
+				MarkdownDescription: "The RFC3339 timestamp of when the provider was last updated",
+			},
+			"can_delete": schema.BoolAttribute{
+				Computed:            true,
+				Description:         "Whether or not the token is permitted to delete the provider",
+				MarkdownDescription: "Whether or not the token is permitted to delete the provider",
+			},
+			"versions": schema.ListNestedAttribute{
+				Computed:            true,
+				Description:         "The published versions of the provider. Always empty for public providers",
+				MarkdownDescription: "The published versions of the provider. Always empty for `public` providers",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"version": schema.StringAttribute{
+							Computed:            true,
+							Description:         "The semantic version",
+							MarkdownDescription: "The semantic version",
+						},
+						"key_id": schema.StringAttribute{
+							Computed:            true,
+							Description:         "The key_id of the GPG key the SHA256SUMS file is signed with",
+							MarkdownDescription: "The key_id of the GPG key the SHA256SUMS file is signed with",
+						},
+						"protocols": schema.ListAttribute{
+							Computed:            true,
+							ElementType:         types.StringType,
+							Description:         "The Terraform plugin protocol versions the version supports",
+							MarkdownDescription: "The Terraform plugin protocol versions the version supports",
+						},
+						"shasums_uploaded": schema.BoolAttribute{
+							Computed:            true,
+							Description:         "Whether or not the SHA256SUMS file has been uploaded",
+							MarkdownDescription: "Whether or not the SHA256SUMS file has been uploaded",
+						},
+						"shasums_sig_uploaded": schema.BoolAttribute{
+							Computed:            true,
+							Description:         "Whether or not the SHA256SUMS.sig file has been uploaded",
+							MarkdownDescription: "Whether or not the SHA256SUMS.sig file has been uploaded",
+						},
+						"created_at": schema.StringAttribute{
+							Computed:            true,
+							Description:         "The RFC3339 timestamp of when the version was created",
+							MarkdownDescription: "The RFC3339 timestamp of when the version was created",
+						},
+					},
+				},
+			},
+			// Input attributes
+			"organization": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Description:         "The organization name under which the provider exists. Defaults to the provider organization",
+				MarkdownDescription: "The organization name under which the provider exists. Defaults to the provider organization",
+			},
+			"namespace": schema.StringAttribute{
+				Required:            true,
+				Description:         "The namespace of the provider",
+				MarkdownDescription: "The namespace of the provider",
+			},
+			"name": schema.StringAttribute{
+				Required:            true,
+				Description:         "The name of the provider",
+				MarkdownDescription: "The name of the provider",
+			},
+			"registry_name": schema.StringAttribute{
+				Required:            true,
+				Description:         "The registry type of the provider. Must be 'public' or 'private'",
+				MarkdownDescription: "The registry type of the provider. Must be 'public' or 'private'",
+				Validators: []validator.String{
+					stringvalidator.OneOf("private", "public"),
+				},
+			},
+		},
+	}
+}
+
+// Metadata returns the data source type name.
+func (d *RegistryProviderDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_registry_provider"
+}
+
+func (d *RegistryProviderDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = recordApiErrors(ctx)
+	var state m.RegistryProviderDataSource
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state.Organization.IsNull() {
+		if d.organization == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("organization"),
+				"Missing organization",
+				"The organization must be set on the data source or the provider, either in the configuration or with the TFE_ORGANIZATION environment variable.",
+			)
+			return
+		}
+		state.Organization = types.StringValue(d.organization)
+	}
+
+	rp, err := d.client.ProviderService.Read(ctx, state.Organization.ValueString(), state.RegistryName.ValueString(), state.Namespace.ValueString(), state.Name.ValueString())
+	if isNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Registry provider not found",
+			"No "+state.RegistryName.ValueString()+" provider "+state.Namespace.ValueString()+"/"+state.Name.ValueString()+" exists in organization "+state.Organization.ValueString(),
+		)
+		return
+	}
+	if err != nil {
+		addApiError(ctx, &resp.Diagnostics, "read the registry provider", err)
+		return
+	}
+
+	state.Id = types.StringValue(rp.Data.Id)
+	state.CreatedAt = types.StringValue(rp.Data.Attributes.CreatedAt.Format(time.RFC3339))
+	state.UpdatedAt = types.StringValue(rp.Data.Attributes.UpdatedAt.Format(time.RFC3339))
+	state.CanDelete = types.BoolValue(rp.Data.Attributes.Permissions.CanDelete)
+	state.Versions = []m.RegistryProviderDataSourceVersion{}
+
+	// Versions are only published to private registries
+	if rp.Data.Attributes.RegistryName == enum.RegistryTypePrivate {
+		versions, err := listRegistryProviderVersions(ctx, d.client, state.Organization.ValueString(), state.Namespace.ValueString(), state.Name.ValueString())
+		if err != nil {
+			addApiError(ctx, &resp.Diagnostics, "list the versions of the registry provider", err)
+			return
+		}
+		for _, version := range versions {
+			protocols := []types.String{}
+			for _, protocol := range version.Attributes.Protocols {
+				protocols = append(protocols, types.StringValue(protocol))
+			}
+			state.Versions = append(state.Versions, m.RegistryProviderDataSourceVersion{
+				Version:            types.StringValue(version.Attributes.Version),
+				KeyId:              types.StringValue(version.Attributes.KeyId),
+				Protocols:          protocols,
+				ShasumsUploaded:    types.BoolValue(version.Attributes.ShasumsUploaded),
+				ShasumsSigUploaded: types.BoolValue(version.Attributes.ShasumsSigUploaded),
+				CreatedAt:          types.StringValue(version.Attributes.CreatedAt.Format(time.RFC3339)),
+			})
+		}
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
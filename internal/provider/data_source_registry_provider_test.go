@@ -0,0 +1,118 @@
+package provider_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/assert"
+	m "github.com/tsanton/terraform-provider-tfepatch/provider/models"
+	u "github.com/tsanton/terraform-provider-tfepatch/utilities"
+)
+
+func Test_provider_registry_provider_data_source(t *testing.T) {
+	useCassette(t)
+	/* Arrange */
+	orgName := u.GetEnv("TFE_ORG_NAME", "")
+	name := "demo-provider-lookup"
+
+	/* Act */
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			//--------------------------------------------------------------------------
+			//--- Read testing
+			//--------------------------------------------------------------------------
+			{
+				Config: providerConfig + fmt.Sprintf(`
+				resource "tfepatch_registry_provider" "this" {
+					organization  = "%[1]s"
+					namespace     = "%[1]s"
+					name          = "%[2]s"
+					registry_name = "private"
+				}
+
+				data "tfepatch_registry_provider" "this" {
+					organization  = tfepatch_registry_provider.this.organization
+					namespace     = tfepatch_registry_provider.this.namespace
+					name          = tfepatch_registry_provider.this.name
+					registry_name = tfepatch_registry_provider.this.registry_name
+				}
+				`, orgName, name),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.tfepatch_registry_provider.this", "id"),
+					resource.TestCheckResourceAttrSet("data.tfepatch_registry_provider.this", "created_at"),
+					resource.TestCheckResourceAttr("data.tfepatch_registry_provider.this", "can_delete", "true"),
+					resource.TestCheckResourceAttr("data.tfepatch_registry_provider.this", "versions.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func Test_registry_provider_data_source_read(t *testing.T) {
+	/* Arrange */
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/organizations/test-org/registry-providers/private/test-org/demo-provider", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		_, _ = w.Write([]byte(`{"data": {"id": "prov-123", "type": "registry-providers", "attributes": {
+			"name": "demo-provider", "namespace": "test-org", "registry-name": "private",
+			"created-at": "2023-01-02T03:04:05Z", "updated-at": "2023-02-03T04:05:06Z", "permissions": {"can-delete": true}}}}`))
+	})
+	mux.HandleFunc("/api/v2/organizations/test-org/registry-providers/private/test-org/demo-provider/versions", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		if r.URL.Query().Get("page[number]") == "1" {
+			_, _ = w.Write([]byte(`{"data": [{"id": "provver-1", "attributes": {"version": "1.0.0", "key-id": "ABCDEF", "protocols": ["5.0"],
+				"shasums-uploaded": true, "shasums-sig-uploaded": true, "created-at": "2023-01-02T03:04:05Z"}}],
+				"meta": {"pagination": {"current-page": 1, "next-page": 2, "total-pages": 2}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": [{"id": "provver-2", "attributes": {"version": "1.1.0", "key-id": "ABCDEF", "protocols": ["5.0", "6.0"],
+			"shasums-uploaded": false, "shasums-sig-uploaded": false, "created-at": "2023-02-03T04:05:06Z"}}],
+			"meta": {"pagination": {"current-page": 2, "next-page": null, "total-pages": 2}}}`))
+	})
+	server := newTestApiServer(t, mux.ServeHTTP)
+	d := newTestDataSource(t, "tfepatch_registry_provider", testProviderValues(server.URL))
+
+	/* Act */
+	resp := readTestDataSource(d, map[string]tftypes.Value{
+		"namespace":     tftypes.NewValue(tftypes.String, "test-org"),
+		"name":          tftypes.NewValue(tftypes.String, "demo-provider"),
+		"registry_name": tftypes.NewValue(tftypes.String, "private"),
+	})
+
+	/* Assert */
+	assert.False(t, resp.Diagnostics.HasError())
+	var state m.RegistryProviderDataSource
+	resp.State.Get(context.Background(), &state)
+	assert.Equal(t, "prov-123", state.Id.ValueString())
+	assert.Equal(t, "test-org", state.Organization.ValueString())
+	assert.Equal(t, "2023-01-02T03:04:05Z", state.CreatedAt.ValueString())
+	assert.Equal(t, "2023-02-03T04:05:06Z", state.UpdatedAt.ValueString())
+	assert.True(t, state.CanDelete.ValueBool())
+	assert.Len(t, state.Versions, 2)
+	assert.Equal(t, "1.0.0", state.Versions[0].Version.ValueString())
+	assert.Equal(t, "1.1.0", state.Versions[1].Version.ValueString())
+	assert.Len(t, state.Versions[1].Protocols, 2)
+	assert.False(t, state.Versions[1].ShasumsUploaded.ValueBool())
+}
+
+func Test_registry_provider_data_source_not_found(t *testing.T) {
+	/* Arrange */
+	server := newTestApiServer(t, notFoundHandler)
+	d := newTestDataSource(t, "tfepatch_registry_provider", testProviderValues(server.URL))
+
+	/* Act */
+	resp := readTestDataSource(d, map[string]tftypes.Value{
+		"namespace":     tftypes.NewValue(tftypes.String, "hashicorp"),
+		"name":          tftypes.NewValue(tftypes.String, "aws"),
+		"registry_name": tftypes.NewValue(tftypes.String, "public"),
+	})
+
+	/* Assert */
+	assert.True(t, resp.Diagnostics.HasError())
+	assert.Equal(t, "Registry provider not found", resp.Diagnostics.Errors()[0].Summary())
+}
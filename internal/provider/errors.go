@@ -0,0 +1,226 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// The TFE client only surfaces the response code of a failed request in the error message
+var apiStatusCodePattern = regexp.MustCompile(`non 200 response: (\d{3})$`)
+
+// maxApiErrorBody caps how much of a failed response is read for its JSON:API errors
+const maxApiErrorBody = 1 << 20
+
+// apiStatusCode extracts the HTTP status code from an error returned by the TFE client.
+func apiStatusCode(err error) (int, bool) {
+	if err == nil {
+		return 0, false
+	}
+	match := apiStatusCodePattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return 0, false
+	}
+	code, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, false
+	}
+	return code, true
+}
+
+// isNotFound reports whether the TFE client error is the result of a 404 response, i.e. the object was deleted out of band.
+func isNotFound(err error) bool {
+	code, ok := apiStatusCode(err)
+	return ok && code == http.StatusNotFound
+}
+
+// isConflict reports whether the TFE client error is the result of a 409 or 422 response, which TFE answers when the object already exists.
+func isConflict(err error) bool {
+	code, ok := apiStatusCode(err)
+	return ok && (code == http.StatusConflict || code == http.StatusUnprocessableEntity)
+}
+
+// apiAttributeNames maps the request attributes that are named differently in the resources to the resource attribute
+var apiAttributeNames = map[string]string{
+	"ascii_armor": "public_key",
+}
+
+// apiError is a JSON:API error object of a failed TFE response.
+type apiError struct {
+	Status string `json:"status"`
+	Title  string `json:"title"`
+	Detail string `json:"detail"`
+	Source struct {
+		Pointer string `json:"pointer"`
+	} `json:"source"`
+}
+
+func (e apiError) message() string {
+	switch {
+	case e.Title != "" && e.Detail != "":
+		return e.Title + ": " + e.Detail
+	case e.Detail != "":
+		return e.Detail
+	}
+	return e.Title
+}
+
+// attribute returns the resource attribute the source pointer of the error points at, e.g. registry_name for /data/attributes/registry-name.
+func (e apiError) attribute() string {
+	if !strings.HasPrefix(e.Source.Pointer, "/data/attributes/") {
+		return ""
+	}
+	name := strings.ReplaceAll(strings.SplitN(strings.TrimPrefix(e.Source.Pointer, "/data/attributes/"), "/", 2)[0], "-", "_")
+	if renamed, ok := apiAttributeNames[name]; ok {
+		return renamed
+	}
+	return name
+}
+
+// parseApiErrors decodes the errors of a JSON:API error document. Some TFE endpoints return plain strings rather than error objects.
+func parseApiErrors(body []byte) []apiError {
+	var document struct {
+		Errors []json.RawMessage `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &document); err != nil {
+		return nil
+	}
+	errs := []apiError{}
+	for _, raw := range document.Errors {
+		var e apiError
+		if err := json.Unmarshal(raw, &e); err == nil {
+			errs = append(errs, e)
+			continue
+		}
+		var detail string
+		if err := json.Unmarshal(raw, &detail); err == nil {
+			errs = append(errs, apiError{Detail: detail})
+		}
+	}
+	return errs
+}
+
+type apiErrorsKey struct{}
+
+// apiResponseErrors holds the JSON:API errors of the last failed TFE response of a request context.
+type apiResponseErrors struct {
+	mu     sync.Mutex
+	status int
+	errors []apiError
+}
+
+// recordApiErrors returns a context in which the transport keeps the JSON:API errors of failed responses for addApiError,
+// as the TFE client discards the body of failed responses.
+func recordApiErrors(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(apiErrorsKey{}).(*apiResponseErrors); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, apiErrorsKey{}, &apiResponseErrors{})
+}
+
+// recordedApiErrors returns the JSON:API errors recorded in the context for a failed response with the status code.
+func recordedApiErrors(ctx context.Context, status int) []apiError {
+	recorded, ok := ctx.Value(apiErrorsKey{}).(*apiResponseErrors)
+	if !ok {
+		return nil
+	}
+	recorded.mu.Lock()
+	defer recorded.mu.Unlock()
+	if recorded.status != status {
+		return nil
+	}
+	return recorded.errors
+}
+
+// apiErrorTransport records the JSON:API errors of failed responses in the request context, see recordApiErrors.
+type apiErrorTransport struct {
+	next http.RoundTripper
+}
+
+func (t *apiErrorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	recorded, ok := req.Context().Value(apiErrorsKey{}).(*apiResponseErrors)
+	if err != nil || !ok || resp.StatusCode < http.StatusBadRequest {
+		return resp, err
+	}
+
+	body, readErr := io.ReadAll(io.LimitReader(resp.Body, maxApiErrorBody))
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if readErr != nil {
+		return resp, nil
+	}
+
+	recorded.mu.Lock()
+	defer recorded.mu.Unlock()
+	recorded.status = resp.StatusCode
+	recorded.errors = parseApiErrors(body)
+	return resp, nil
+}
+
+// apiErrorSummary returns the diagnostic summary of a failed response and what to do about it.
+func apiErrorSummary(status int) (string, string) {
+	switch status {
+	case http.StatusUnauthorized:
+		return "Invalid TFE token", "Check the token of the provider, the TFE_TOKEN environment variable or the credentials file, and that the token has neither expired nor been revoked."
+	case http.StatusForbidden:
+		return "Insufficient TFE permissions", "The token must belong to the owners team or to a team with the Manage Private Registry permission."
+	case http.StatusNotFound:
+		return "TFE object not found", "The object or its organization does not exist, or the token is not allowed to access it."
+	case http.StatusConflict:
+		return "TFE object already exists", "Import the existing object, or delete it before creating it again."
+	case http.StatusUnprocessableEntity:
+		return "Invalid TFE request", "Terraform Cloud rejected the values of the request."
+	}
+	return fmt.Sprintf("TFE request failed with status %d", status), ""
+}
+
+// addApiError adds a diagnostic for an error of the TFE client while trying to perform the action, e.g. "create the registry provider".
+// Errors of the response pointing at one of the attributes are attached to the attribute.
+func addApiError(ctx context.Context, diags *diag.Diagnostics, action string, err error, attributes ...string) {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		diags.AddError("TFE request timed out", fmt.Sprintf("Could not %s before the timeout of the operation: %s. Raise the timeout in the timeouts block of the resource", action, err.Error()))
+		return
+	}
+	status, ok := apiStatusCode(err)
+	if !ok {
+		diags.AddError("TFE request failed", fmt.Sprintf("Could not %s: %s", action, err.Error()))
+		return
+	}
+
+	summary, advice := apiErrorSummary(status)
+	errs := recordedApiErrors(ctx, status)
+	if len(errs) == 0 {
+		diags.AddError(summary, strings.TrimSpace(fmt.Sprintf("Could not %s, Terraform Cloud responded %d %s. %s", action, status, http.StatusText(status), advice)))
+		return
+	}
+	for _, e := range errs {
+		detail := strings.TrimSpace(fmt.Sprintf("Could not %s: %s. %s", action, strings.TrimSuffix(e.message(), "."), advice))
+		attribute := e.attribute()
+		if attribute != "" && contains(attributes, attribute) {
+			diags.AddAttributeError(path.Root(attribute), summary, detail)
+		} else {
+			diags.AddError(summary, detail)
+		}
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
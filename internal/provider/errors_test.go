@@ -0,0 +1,162 @@
+package provider_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	m "github.com/tsanton/terraform-provider-tfepatch/provider/models"
+)
+
+func Test_api_errors_on_create(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		summary   string
+		detail    string
+		attribute *path.Path
+	}{
+		{
+			name:    "unauthorized",
+			status:  http.StatusUnauthorized,
+			body:    `{"errors":[{"status":"401","title":"unauthorized"}]}`,
+			summary: "Invalid TFE token",
+			detail:  "Could not create the registry provider: unauthorized. Check the token of the provider",
+		},
+		{
+			name:    "forbidden",
+			status:  http.StatusForbidden,
+			body:    `{"errors":[{"status":"403","title":"forbidden"}]}`,
+			summary: "Insufficient TFE permissions",
+			detail:  "Manage Private Registry permission",
+		},
+		{
+			name:    "not found as plain string",
+			status:  http.StatusNotFound,
+			body:    `{"errors":["not found"]}`,
+			summary: "TFE object not found",
+			detail:  "Could not create the registry provider: not found. The object or its organization does not exist",
+		},
+		{
+			name:    "conflict",
+			status:  http.StatusConflict,
+			body:    `{"errors":[{"status":"409","title":"conflict","detail":"Name has already been taken"}]}`,
+			summary: "TFE object already exists",
+			detail:  "Could not create the registry provider: conflict: Name has already been taken. Import the existing object",
+		},
+		{
+			name:      "unprocessable attribute",
+			status:    http.StatusUnprocessableEntity,
+			body:      `{"errors":[{"status":"422","title":"invalid attribute","detail":"Registry name is not included in the list","source":{"pointer":"/data/attributes/registry-name"}}]}`,
+			summary:   "Invalid TFE request",
+			detail:    "Could not create the registry provider: invalid attribute: Registry name is not included in the list.",
+			attribute: pathPointer(path.Root("registry_name")),
+		},
+		{
+			name:    "without body",
+			status:  http.StatusInternalServerError,
+			summary: "TFE request failed with status 500",
+			detail:  "Could not create the registry provider, Terraform Cloud responded 500 Internal Server Error.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* Arrange */
+			server := newTestApiServer(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/vnd.api+json")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+			r := newTestResource(t, "tfepatch_registry_provider", testProviderValues(server.URL))
+			planReq := newTestModifyPlanRequest(t, r, map[string]tftypes.Value{
+				"organization":  tftypes.NewValue(tftypes.String, "test-org"),
+				"namespace":     tftypes.NewValue(tftypes.String, "test-org"),
+				"name":          tftypes.NewValue(tftypes.String, "demo-provider"),
+				"registry_name": tftypes.NewValue(tftypes.String, "private"),
+			}, nil)
+			resp := fwresource.CreateResponse{State: tfsdk.State{Schema: planReq.State.Schema, Raw: planReq.State.Raw}}
+
+			/* Act */
+			r.Create(context.Background(), fwresource.CreateRequest{Config: planReq.Config, Plan: planReq.Plan}, &resp)
+
+			/* Assert */
+			assert.Equal(t, 1, resp.Diagnostics.ErrorsCount())
+			diagnostic := resp.Diagnostics.Errors()[0]
+			assert.Equal(t, tt.summary, diagnostic.Summary())
+			assert.Contains(t, diagnostic.Detail(), tt.detail)
+			withPath, ok := diagnostic.(interface{ Path() path.Path })
+			assert.Equal(t, tt.attribute != nil, ok)
+			if tt.attribute != nil && ok {
+				assert.Equal(t, *tt.attribute, withPath.Path())
+			}
+		})
+	}
+}
+
+func Test_api_errors_on_delete(t *testing.T) {
+	/* Arrange */
+	server := newTestApiServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"errors":[{"status":"403","title":"forbidden"}]}`))
+	})
+	r := newTestResource(t, "tfepatch_registry_provider", testProviderValues(server.URL))
+	state := newTestState(t, r, &m.RegistryProvider{
+		Id:           types.StringValue("test-org||demo-provider||private"),
+		Organization: types.StringValue("test-org"),
+		Namespace:    types.StringValue("test-org"),
+		Name:         types.StringValue("demo-provider"),
+		RegistryName: types.StringValue("private"),
+	})
+	resp := fwresource.DeleteResponse{State: state}
+
+	/* Act */
+	r.Delete(context.Background(), fwresource.DeleteRequest{State: state}, &resp)
+
+	/* Assert */
+	assert.Equal(t, 1, resp.Diagnostics.ErrorsCount())
+	assert.Equal(t, "Insufficient TFE permissions", resp.Diagnostics.Errors()[0].Summary())
+	assert.Contains(t, resp.Diagnostics.Errors()[0].Detail(), "Could not delete the registry provider: forbidden.")
+}
+
+func Test_api_errors_attach_to_renamed_attributes(t *testing.T) {
+	/* Arrange */
+	server := newTestApiServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"errors":[{"status":"422","title":"invalid attribute","detail":"ASCII armor is invalid","source":{"pointer":"/data/attributes/ascii-armor"}}]}`))
+	})
+	r := newTestResource(t, "tfepatch_gpg_key", testProviderValues(server.URL))
+	publicKey := armoredGpgKeys(t, openpgp.PublicKeyType, loadCassetteGpgEntity(t))
+	planReq := newTestModifyPlanRequest(t, r, map[string]tftypes.Value{
+		"organization":    tftypes.NewValue(tftypes.String, "test-org"),
+		"namespace":       tftypes.NewValue(tftypes.String, "test-org"),
+		"public_key":      tftypes.NewValue(tftypes.String, publicKey),
+		"retain_previous": tftypes.NewValue(tftypes.Bool, false),
+	}, nil)
+	resp := fwresource.CreateResponse{State: tfsdk.State{Schema: planReq.State.Schema, Raw: planReq.State.Raw}}
+
+	/* Act */
+	r.Create(context.Background(), fwresource.CreateRequest{Config: planReq.Config, Plan: planReq.Plan}, &resp)
+
+	/* Assert */
+	assert.Equal(t, 1, resp.Diagnostics.ErrorsCount())
+	assert.Equal(t, "Invalid TFE request", resp.Diagnostics.Errors()[0].Summary())
+	withPath, ok := resp.Diagnostics.Errors()[0].(interface{ Path() path.Path })
+	assert.True(t, ok)
+	if ok {
+		assert.Equal(t, path.Root("public_key"), withPath.Path())
+	}
+}
+
+func pathPointer(p path.Path) *path.Path {
+	return &p
+}
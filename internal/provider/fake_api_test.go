@@ -0,0 +1,702 @@
+package provider_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/stretchr/testify/assert"
+	api "github.com/tsanton/tfe-client/tfe"
+	apim "github.com/tsanton/tfe-client/tfe/models"
+	apir "github.com/tsanton/tfe-client/tfe/models/request"
+)
+
+const (
+	fakeOrganization = "test-org"
+	fakeToken        = "fake-token"
+	fakePageSize     = 20
+)
+
+// fakeTfeApi is an in-process stand-in for the registry-providers, versions, platforms and gpg-keys endpoints of the TFE API.
+// It holds its objects in memory for the lifetime of a test and serves the upload links of versions and platforms itself.
+type fakeTfeApi struct {
+	server *httptest.Server
+
+	mu        sync.Mutex
+	nextId    int
+	providers []*fakeRegistryProvider
+	gpgKeys   []*fakeGpgKey
+	// uploads maps the id of an upload link to the function storing the uploaded content
+	uploads map[string]func([]byte)
+}
+
+type fakeRegistryProvider struct {
+	id           string
+	organization string
+	registryName string
+	namespace    string
+	name         string
+	createdAt    time.Time
+	versions     []*fakeProviderVersion
+}
+
+type fakeProviderVersion struct {
+	id                 string
+	version            string
+	keyId              string
+	protocols          []string
+	createdAt          time.Time
+	shasums            []byte
+	shasumsSig         []byte
+	shasumsUploadId    string
+	shasumsSigUploadId string
+	platforms          []*fakeProviderPlatform
+}
+
+type fakeProviderPlatform struct {
+	id       string
+	os       string
+	arch     string
+	filename string
+	shasum   string
+	binary   []byte
+	uploadId string
+}
+
+type fakeGpgKey struct {
+	id         string
+	namespace  string
+	keyId      string
+	asciiArmor string
+	createdAt  time.Time
+}
+
+// newFakeTfeApi starts a fake TFE API for the organization test-org that is closed when the test completes.
+func newFakeTfeApi(t *testing.T) *fakeTfeApi {
+	api := &fakeTfeApi{uploads: map[string]func([]byte){}}
+	api.server = httptest.NewServer(http.HandlerFunc(api.serveHTTP))
+	t.Cleanup(api.server.Close)
+	return api
+}
+
+// providerConfig returns the provider block configuring the provider against the fake.
+func (a *fakeTfeApi) providerConfig() string {
+	return fmt.Sprintf(`
+provider "tfepatch" {
+	hostname     = "%s"
+	token        = "%s"
+	organization = "%s"
+	max_retries  = 0
+}
+`, a.server.URL, fakeToken, fakeOrganization)
+}
+
+// requireTerraformCli skips the test when no Terraform CLI is available to run it, as the fake does not serve releases.hashicorp.com.
+func requireTerraformCli(t *testing.T) {
+	if os.Getenv("TF_ACC_TERRAFORM_PATH") != "" {
+		return
+	}
+	terraformPath, err := exec.LookPath("terraform")
+	if err != nil {
+		t.Skip("the terraform binary is not on the PATH and TF_ACC_TERRAFORM_PATH is not set")
+	}
+	t.Setenv("TF_ACC_TERRAFORM_PATH", terraformPath)
+}
+
+func (a *fakeTfeApi) registryProvider(registryName, namespace, name string) *fakeRegistryProvider {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.findRegistryProvider(fakeOrganization, registryName, namespace, name)
+}
+
+func (a *fakeTfeApi) gpgKey(namespace, keyId string) *fakeGpgKey {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.findGpgKey(namespace, keyId)
+}
+
+func (a *fakeTfeApi) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	// Upload links are authenticated by the link itself
+	if len(segments) == 2 && segments[0] == "_archivist" {
+		a.serveUpload(w, r, segments[1])
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+fakeToken {
+		writeFakeError(w, http.StatusUnauthorized, "unauthorized", "")
+		return
+	}
+
+	switch {
+	case len(segments) >= 5 && strings.Join(segments[:2], "/") == "api/v2" && segments[2] == "organizations" && segments[4] == "registry-providers":
+		if !strings.EqualFold(segments[3], fakeOrganization) {
+			writeFakeError(w, http.StatusNotFound, "not found", "")
+			return
+		}
+		a.serveRegistryProviders(w, r, segments[3], segments[5:])
+	case len(segments) >= 5 && strings.Join(segments[:5], "/") == "api/registry/private/v2/gpg-keys":
+		a.serveGpgKeys(w, r, segments[5:])
+	default:
+		writeFakeError(w, http.StatusNotFound, "not found", "")
+	}
+}
+
+func (a *fakeTfeApi) serveRegistryProviders(w http.ResponseWriter, r *http.Request, organization string, segments []string) {
+	if len(segments) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			items := []interface{}{}
+			for _, provider := range a.providers {
+				if registryName := r.URL.Query().Get("filter[registry_name]"); registryName != "" && registryName != provider.registryName {
+					continue
+				}
+				items = append(items, provider.resource())
+			}
+			writeFakeList(w, r, items)
+		case http.MethodPost:
+			var body apir.Provider
+			if !readFakeBody(w, r, &body) {
+				return
+			}
+			attributes := body.Data.Attributes
+			registryName := string(attributes.RegistryName)
+			switch {
+			case registryName != "private" && registryName != "public":
+				writeFakeError(w, http.StatusUnprocessableEntity, "invalid attribute", "Registry name is not included in the list")
+			case registryName == "private" && !strings.EqualFold(attributes.Namespace, organization):
+				writeFakeError(w, http.StatusUnprocessableEntity, "invalid attribute", "Namespace must match the organization name")
+			case a.findRegistryProvider(organization, registryName, attributes.Namespace, attributes.Name) != nil:
+				writeFakeError(w, http.StatusUnprocessableEntity, "invalid attribute", "Name has already been taken")
+			default:
+				provider := &fakeRegistryProvider{
+					id:           a.newId("prov"),
+					organization: organization,
+					registryName: registryName,
+					namespace:    attributes.Namespace,
+					name:         attributes.Name,
+					createdAt:    fakeTimestamp(),
+				}
+				a.providers = append(a.providers, provider)
+				writeFakeJson(w, http.StatusCreated, map[string]interface{}{"data": provider.resource()})
+			}
+		default:
+			writeFakeError(w, http.StatusMethodNotAllowed, "method not allowed", "")
+		}
+		return
+	}
+
+	if len(segments) < 3 {
+		writeFakeError(w, http.StatusNotFound, "not found", "")
+		return
+	}
+	provider := a.findRegistryProvider(organization, segments[0], segments[1], segments[2])
+	if provider == nil {
+		writeFakeError(w, http.StatusNotFound, "not found", "")
+		return
+	}
+	if len(segments) == 3 {
+		switch r.Method {
+		case http.MethodGet:
+			writeFakeJson(w, http.StatusOK, map[string]interface{}{"data": provider.resource()})
+		case http.MethodDelete:
+			for i, candidate := range a.providers {
+				if candidate == provider {
+					a.providers = append(a.providers[:i], a.providers[i+1:]...)
+					break
+				}
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeFakeError(w, http.StatusMethodNotAllowed, "method not allowed", "")
+		}
+		return
+	}
+	if segments[3] != "versions" || provider.registryName != "private" {
+		writeFakeError(w, http.StatusNotFound, "not found", "")
+		return
+	}
+	a.serveVersions(w, r, provider, segments[4:])
+}
+
+func (a *fakeTfeApi) serveVersions(w http.ResponseWriter, r *http.Request, provider *fakeRegistryProvider, segments []string) {
+	if len(segments) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			items := []interface{}{}
+			for _, version := range provider.versions {
+				items = append(items, a.versionResource(version))
+			}
+			writeFakeList(w, r, items)
+		case http.MethodPost:
+			var body apir.ProviderVersion
+			if !readFakeBody(w, r, &body) {
+				return
+			}
+			attributes := body.Data.Attributes
+			switch {
+			case attributes.Version == "" || len(attributes.Protocols) == 0:
+				writeFakeError(w, http.StatusUnprocessableEntity, "invalid attribute", "Version and protocols can't be blank")
+			case a.findGpgKey(provider.namespace, attributes.KeyId) == nil:
+				writeFakeError(w, http.StatusUnprocessableEntity, "invalid attribute", "Key ID was not found in the namespace")
+			case findProviderVersion(provider, attributes.Version) != nil:
+				writeFakeError(w, http.StatusUnprocessableEntity, "invalid attribute", "Version has already been taken")
+			default:
+				version := &fakeProviderVersion{
+					id:        a.newId("provver"),
+					version:   attributes.Version,
+					keyId:     attributes.KeyId,
+					protocols: attributes.Protocols,
+					createdAt: fakeTimestamp(),
+				}
+				version.shasumsUploadId = a.newUpload(func(content []byte) { version.shasums = content })
+				version.shasumsSigUploadId = a.newUpload(func(content []byte) { version.shasumsSig = content })
+				provider.versions = append(provider.versions, version)
+				writeFakeJson(w, http.StatusCreated, map[string]interface{}{"data": a.versionResource(version)})
+			}
+		default:
+			writeFakeError(w, http.StatusMethodNotAllowed, "method not allowed", "")
+		}
+		return
+	}
+
+	version := findProviderVersion(provider, segments[0])
+	if version == nil {
+		writeFakeError(w, http.StatusNotFound, "not found", "")
+		return
+	}
+	if len(segments) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			writeFakeJson(w, http.StatusOK, map[string]interface{}{"data": a.versionResource(version)})
+		case http.MethodDelete:
+			for i, candidate := range provider.versions {
+				if candidate == version {
+					provider.versions = append(provider.versions[:i], provider.versions[i+1:]...)
+					break
+				}
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeFakeError(w, http.StatusMethodNotAllowed, "method not allowed", "")
+		}
+		return
+	}
+	if segments[1] != "platforms" {
+		writeFakeError(w, http.StatusNotFound, "not found", "")
+		return
+	}
+	a.servePlatforms(w, r, version, segments[2:])
+}
+
+func (a *fakeTfeApi) servePlatforms(w http.ResponseWriter, r *http.Request, version *fakeProviderVersion, segments []string) {
+	if len(segments) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			items := []interface{}{}
+			for _, platform := range version.platforms {
+				items = append(items, a.platformResource(version, platform))
+			}
+			writeFakeList(w, r, items)
+		case http.MethodPost:
+			var body apir.ProviderVersionPlatform
+			if !readFakeBody(w, r, &body) {
+				return
+			}
+			attributes := body.Data.Attributes
+			switch {
+			case attributes.Os == "" || attributes.Arch == "" || attributes.Shasum == "" || attributes.Filname == "":
+				writeFakeError(w, http.StatusUnprocessableEntity, "invalid attribute", "Os, arch, shasum and filename can't be blank")
+			case findProviderPlatform(version, attributes.Os, attributes.Arch) != nil:
+				writeFakeError(w, http.StatusUnprocessableEntity, "invalid attribute", "Platform has already been taken")
+			default:
+				platform := &fakeProviderPlatform{
+					id:       a.newId("provpltfrm"),
+					os:       attributes.Os,
+					arch:     attributes.Arch,
+					filename: attributes.Filname,
+					shasum:   attributes.Shasum,
+				}
+				platform.uploadId = a.newUpload(func(content []byte) { platform.binary = content })
+				version.platforms = append(version.platforms, platform)
+				writeFakeJson(w, http.StatusCreated, map[string]interface{}{"data": a.platformResource(version, platform)})
+			}
+		default:
+			writeFakeError(w, http.StatusMethodNotAllowed, "method not allowed", "")
+		}
+		return
+	}
+
+	if len(segments) != 2 {
+		writeFakeError(w, http.StatusNotFound, "not found", "")
+		return
+	}
+	platform := findProviderPlatform(version, segments[0], segments[1])
+	if platform == nil {
+		writeFakeError(w, http.StatusNotFound, "not found", "")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeFakeJson(w, http.StatusOK, map[string]interface{}{"data": a.platformResource(version, platform)})
+	case http.MethodDelete:
+		for i, candidate := range version.platforms {
+			if candidate == platform {
+				version.platforms = append(version.platforms[:i], version.platforms[i+1:]...)
+				break
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeFakeError(w, http.StatusMethodNotAllowed, "method not allowed", "")
+	}
+}
+
+func (a *fakeTfeApi) serveGpgKeys(w http.ResponseWriter, r *http.Request, segments []string) {
+	if len(segments) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			namespaces := map[string]bool{}
+			for _, namespace := range strings.Split(r.URL.Query().Get("filter[namespace]"), ",") {
+				namespaces[namespace] = true
+			}
+			items := []interface{}{}
+			for _, key := range a.gpgKeys {
+				if namespaces[key.namespace] {
+					items = append(items, key.resource())
+				}
+			}
+			writeFakeList(w, r, items)
+		case http.MethodPost:
+			var body apir.Gpg
+			if !readFakeBody(w, r, &body) {
+				return
+			}
+			attributes := body.Data.Attributes
+			entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(attributes.AsciiArmor))
+			switch {
+			case !strings.EqualFold(attributes.Namespace, fakeOrganization):
+				writeFakeError(w, http.StatusUnprocessableEntity, "invalid attribute", "Namespace must match the organization name")
+			case err != nil || len(entities) != 1:
+				writeFakeError(w, http.StatusUnprocessableEntity, "invalid attribute", "ASCII armor is not a valid public key")
+			case a.findGpgKey(attributes.Namespace, fmt.Sprintf("%016X", entities[0].PrimaryKey.KeyId)) != nil:
+				writeFakeError(w, http.StatusUnprocessableEntity, "invalid attribute", "Key ID has already been taken")
+			default:
+				key := &fakeGpgKey{
+					id:         a.newId(""),
+					namespace:  attributes.Namespace,
+					keyId:      fmt.Sprintf("%016X", entities[0].PrimaryKey.KeyId),
+					asciiArmor: attributes.AsciiArmor,
+					createdAt:  fakeTimestamp(),
+				}
+				a.gpgKeys = append(a.gpgKeys, key)
+				writeFakeJson(w, http.StatusCreated, map[string]interface{}{"data": key.resource()})
+			}
+		default:
+			writeFakeError(w, http.StatusMethodNotAllowed, "method not allowed", "")
+		}
+		return
+	}
+
+	key := (*fakeGpgKey)(nil)
+	if len(segments) == 2 {
+		key = a.findGpgKey(segments[0], segments[1])
+	}
+	if key == nil {
+		writeFakeError(w, http.StatusNotFound, "not found", "")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeFakeJson(w, http.StatusOK, map[string]interface{}{"data": key.resource()})
+	case http.MethodDelete:
+		for i, candidate := range a.gpgKeys {
+			if candidate == key {
+				a.gpgKeys = append(a.gpgKeys[:i], a.gpgKeys[i+1:]...)
+				break
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeFakeError(w, http.StatusMethodNotAllowed, "method not allowed", "")
+	}
+}
+
+func (a *fakeTfeApi) serveUpload(w http.ResponseWriter, r *http.Request, uploadId string) {
+	store, ok := a.uploads[uploadId]
+	if !ok || r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	content, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	store(content)
+	// Upload links can only be used once
+	delete(a.uploads, uploadId)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (a *fakeTfeApi) findRegistryProvider(organization, registryName, namespace, name string) *fakeRegistryProvider {
+	for _, provider := range a.providers {
+		if strings.EqualFold(provider.organization, organization) && provider.registryName == registryName &&
+			strings.EqualFold(provider.namespace, namespace) && strings.EqualFold(provider.name, name) {
+			return provider
+		}
+	}
+	return nil
+}
+
+func (a *fakeTfeApi) findGpgKey(namespace, keyId string) *fakeGpgKey {
+	for _, key := range a.gpgKeys {
+		if strings.EqualFold(key.namespace, namespace) && strings.EqualFold(key.keyId, keyId) {
+			return key
+		}
+	}
+	return nil
+}
+
+func findProviderVersion(provider *fakeRegistryProvider, version string) *fakeProviderVersion {
+	for _, candidate := range provider.versions {
+		if candidate.version == version {
+			return candidate
+		}
+	}
+	return nil
+}
+
+func findProviderPlatform(version *fakeProviderVersion, os, arch string) *fakeProviderPlatform {
+	for _, candidate := range version.platforms {
+		if candidate.os == os && candidate.arch == arch {
+			return candidate
+		}
+	}
+	return nil
+}
+
+func (a *fakeTfeApi) newId(prefix string) string {
+	a.nextId++
+	if prefix == "" {
+		return strconv.Itoa(a.nextId)
+	}
+	return fmt.Sprintf("%s-%d", prefix, a.nextId)
+}
+
+// newUpload registers a single use upload link and returns its id.
+func (a *fakeTfeApi) newUpload(store func([]byte)) string {
+	uploadId := a.newId("upload")
+	a.uploads[uploadId] = store
+	return uploadId
+}
+
+func (a *fakeTfeApi) uploadUrl(uploadId string) string {
+	return a.server.URL + "/_archivist/" + uploadId
+}
+
+func (p *fakeRegistryProvider) resource() map[string]interface{} {
+	return map[string]interface{}{
+		"id":   p.id,
+		"type": "registry-providers",
+		"attributes": map[string]interface{}{
+			"name":          p.name,
+			"namespace":     p.namespace,
+			"registry-name": p.registryName,
+			"created-at":    p.createdAt,
+			"updated-at":    p.createdAt,
+			"permissions":   map[string]interface{}{"can-delete": true},
+		},
+		"relationships": map[string]interface{}{
+			"organization": map[string]interface{}{"data": map[string]interface{}{"id": p.organization, "type": "organizations"}},
+		},
+	}
+}
+
+func (a *fakeTfeApi) versionResource(v *fakeProviderVersion) map[string]interface{} {
+	links := map[string]interface{}{}
+	if v.shasums == nil {
+		links["shasums-upload"] = a.uploadUrl(v.shasumsUploadId)
+	} else {
+		links["shasums-download"] = a.server.URL + "/_archivist/" + v.id + "-shasums"
+	}
+	if v.shasumsSig == nil {
+		links["shasums-sig-upload"] = a.uploadUrl(v.shasumsSigUploadId)
+	} else {
+		links["shasums-sig-download"] = a.server.URL + "/_archivist/" + v.id + "-shasums-sig"
+	}
+	return map[string]interface{}{
+		"id":   v.id,
+		"type": "registry-provider-versions",
+		"attributes": map[string]interface{}{
+			"version":              v.version,
+			"key-id":               v.keyId,
+			"protocols":            v.protocols,
+			"created-at":           v.createdAt,
+			"updated-at":           v.createdAt,
+			"shasums-uploaded":     v.shasums != nil,
+			"shasums-sig-uploaded": v.shasumsSig != nil,
+			"permissions":          map[string]interface{}{"can-delete": true, "can-upload-asset": true},
+		},
+		"links": links,
+	}
+}
+
+func (a *fakeTfeApi) platformResource(v *fakeProviderVersion, p *fakeProviderPlatform) map[string]interface{} {
+	links := map[string]interface{}{}
+	if p.binary == nil {
+		links["provider-binary-upload"] = a.uploadUrl(p.uploadId)
+	}
+	return map[string]interface{}{
+		"id":   p.id,
+		"type": "registry-provider-platforms",
+		"attributes": map[string]interface{}{
+			"os":                       p.os,
+			"arch":                     p.arch,
+			"filename":                 p.filename,
+			"shasum":                   p.shasum,
+			"provider-binary-uploaded": p.binary != nil,
+			"permissions":              map[string]interface{}{"can-delete": true, "can-upload-asset": true},
+		},
+		"relationships": map[string]interface{}{
+			"registry-provider-version": map[string]interface{}{"data": map[string]interface{}{"id": v.id, "type": "registry-provider-versions"}},
+		},
+		"links": links,
+	}
+}
+
+func (k *fakeGpgKey) resource() map[string]interface{} {
+	return map[string]interface{}{
+		"id":   k.id,
+		"type": "gpg-keys",
+		"attributes": map[string]interface{}{
+			"ascii-armor": k.asciiArmor,
+			"key-id":      k.keyId,
+			"namespace":   k.namespace,
+			"source":      "TerraformCloud",
+			"created-at":  k.createdAt,
+			"updated-at":  k.createdAt,
+		},
+		"links": map[string]interface{}{"self": "/v2/gpg-keys/" + k.id},
+	}
+}
+
+func fakeTimestamp() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+// readFakeBody decodes the JSON:API request body, answering 400 when it cannot.
+func readFakeBody(w http.ResponseWriter, r *http.Request, body interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+		writeFakeError(w, http.StatusBadRequest, "bad request", err.Error())
+		return false
+	}
+	return true
+}
+
+// writeFakeList writes a page of the items, honoring the page[number] and page[size] query parameters.
+func writeFakeList(w http.ResponseWriter, r *http.Request, items []interface{}) {
+	pageNumber, err := strconv.Atoi(r.URL.Query().Get("page[number]"))
+	if err != nil || pageNumber < 1 {
+		pageNumber = 1
+	}
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("page[size]"))
+	if err != nil || pageSize < 1 {
+		pageSize = fakePageSize
+	}
+	totalPages := (len(items) + pageSize - 1) / pageSize
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	page := []interface{}{}
+	if start := (pageNumber - 1) * pageSize; start < len(items) {
+		end := start + pageSize
+		if end > len(items) {
+			end = len(items)
+		}
+		page = items[start:end]
+	}
+	pagination := map[string]interface{}{
+		"current-page": pageNumber,
+		"page-size":    pageSize,
+		"prev-page":    nil,
+		"next-page":    nil,
+		"total-pages":  totalPages,
+		"total-count":  len(items),
+	}
+	if pageNumber > 1 {
+		pagination["prev-page"] = pageNumber - 1
+	}
+	if pageNumber < totalPages {
+		pagination["next-page"] = pageNumber + 1
+	}
+	writeFakeJson(w, http.StatusOK, map[string]interface{}{"data": page, "meta": map[string]interface{}{"pagination": pagination}})
+}
+
+func writeFakeError(w http.ResponseWriter, status int, title, detail string) {
+	writeFakeJson(w, status, map[string]interface{}{
+		"errors": []interface{}{map[string]interface{}{"status": strconv.Itoa(status), "title": title, "detail": detail}},
+	})
+}
+
+func writeFakeJson(w http.ResponseWriter, status int, body interface{}) {
+	content, _ := json.Marshal(body)
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	w.WriteHeader(status)
+	_, _ = w.Write(content)
+}
+
+func Test_fake_tfe_api_serves_the_tfe_client(t *testing.T) {
+	/* Arrange */
+	fake := newFakeTfeApi(t)
+	client, err := api.NewClient(logger, &apim.ClientConfig{Address: fake.server.URL, Token: fakeToken})
+	assert.Nil(t, err)
+	ctx := context.Background()
+	publicKey := armoredGpgKeys(t, openpgp.PublicKeyType, newTestGpgEntity(t, time.Now(), 0))
+
+	/* Act */
+	_, providerErr := client.ProviderService.Create(ctx, fakeOrganization, &apir.Provider{Data: apir.ProviderData{
+		Type:       "registry-providers",
+		Attributes: apir.ProviderDataAttributes{Name: "demo-provider", Namespace: fakeOrganization, RegistryName: "private"},
+	}})
+	key, keyErr := client.GpgService.Create(ctx, &apir.Gpg{Data: apir.GpgData{
+		Type:       "gpg-keys",
+		Attributes: apir.GpgDataAttributes{AsciiArmor: publicKey, Namespace: fakeOrganization},
+	}})
+	version, versionErr := client.ProviderVersionService.Create(ctx, fakeOrganization, fakeOrganization, "demo-provider", &apir.ProviderVersion{Data: apir.ProviderVersionData{
+		Type:       "registry-provider-versions",
+		Attributes: apir.ProviderVersionDataAttributes{Version: "1.0.0", KeyId: key.Data.Attributes.KeyId, Protocols: []string{"6.0"}},
+	}})
+	uploadResp, uploadErr := http.Post(version.Data.Links.ShasumsUploadUrl, "application/octet-stream", strings.NewReader("shasums"))
+	missingErr := client.ProviderService.Delete(ctx, fakeOrganization, "private", fakeOrganization, "missing-provider")
+	read, readErr := client.ProviderVersionService.Read(ctx, fakeOrganization, fakeOrganization, "demo-provider", "1.0.0")
+
+	/* Assert */
+	assert.Nil(t, providerErr)
+	assert.Nil(t, keyErr)
+	assert.Nil(t, versionErr)
+	assert.Nil(t, uploadErr)
+	// Upload links only accept PUT
+	assert.Equal(t, http.StatusNotFound, uploadResp.StatusCode)
+	assert.Contains(t, missingErr.Error(), "404")
+	assert.Nil(t, readErr)
+	assert.Equal(t, key.Data.Attributes.KeyId, read.Data.Attributes.KeyId)
+	assert.False(t, read.Data.Attributes.ShasumsUploaded)
+	assert.NotNil(t, fake.gpgKey(fakeOrganization, key.Data.Attributes.KeyId))
+	assert.Len(t, fake.registryProvider("private", fakeOrganization, "demo-provider").versions, 1)
+}
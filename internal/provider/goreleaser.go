@@ -0,0 +1,142 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// goreleaserArtifact is an entry of the artifacts.json file goreleaser writes to its dist directory.
+type goreleaserArtifact struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Goos   string `json:"goos"`
+	Goarch string `json:"goarch"`
+	// Goarm and Goamd64 are the variants of arm and amd64 builds, i.e. "7" and "v1"
+	Goarm   string `json:"goarm"`
+	Goamd64 string `json:"goamd64"`
+	Type    string `json:"type"`
+}
+
+// goreleaserMetadata is the content of the metadata.json file goreleaser writes to its dist directory.
+type goreleaserMetadata struct {
+	ProjectName string `json:"project_name"`
+	Tag         string `json:"tag"`
+	Version     string `json:"version"`
+}
+
+// goreleaserRelease holds the files of a goreleaser release that are published to the registry.
+type goreleaserRelease struct {
+	Version        string
+	ShasumsPath    string
+	ShasumsSigPath string
+	Platforms      []goreleaserPlatform
+}
+
+type goreleaserPlatform struct {
+	Os          string
+	Arch        string
+	Filename    string
+	ArchivePath string
+}
+
+// readGoreleaserRelease parses the artifacts.json (and metadata.json if version is empty) found in distDir.
+// Artifact paths are resolved relative to distDir as goreleaser records them relative to the directory it ran in.
+func readGoreleaserRelease(distDir, artifactsPath, version string) (*goreleaserRelease, error) {
+	if artifactsPath == "" {
+		artifactsPath = filepath.Join(distDir, "artifacts.json")
+	}
+	if distDir == "" {
+		distDir = filepath.Dir(artifactsPath)
+	}
+
+	content, err := os.ReadFile(artifactsPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read goreleaser artifacts: %w", err)
+	}
+	var artifacts []goreleaserArtifact
+	if err = json.Unmarshal(content, &artifacts); err != nil {
+		return nil, fmt.Errorf("unable to parse goreleaser artifacts %s: %w", artifactsPath, err)
+	}
+
+	if version == "" {
+		metadataPath := filepath.Join(distDir, "metadata.json")
+		content, err = os.ReadFile(metadataPath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read goreleaser metadata, set the version explicitly: %w", err)
+		}
+		var metadata goreleaserMetadata
+		if err = json.Unmarshal(content, &metadata); err != nil {
+			return nil, fmt.Errorf("unable to parse goreleaser metadata %s: %w", metadataPath, err)
+		}
+		version = metadata.Version
+	}
+
+	release := goreleaserRelease{Version: version}
+	// goreleaser may sign the archives as well, so the signature of the checksum file is found by its name
+	var checksumName string
+	signatures := map[string]string{}
+	// The registry holds one archive per os and arch, so variants of a platform cannot be told apart
+	archives := map[string]goreleaserArtifact{}
+	for _, artifact := range artifacts {
+		artifactPath := filepath.Join(distDir, filepath.Base(artifact.Path))
+		switch artifact.Type {
+		case "Archive":
+			key := platformKey(artifact.Goos, artifact.Goarch)
+			if other, ok := archives[key]; ok {
+				return nil, fmt.Errorf("the goreleaser artifacts in %s hold the archives %s (%s) and %s (%s) for the %s platform, which the registry holds a single archive of. Build a single goarm and goamd64 variant",
+					artifactsPath, other.Name, other.variant(), artifact.Name, artifact.variant(), key)
+			}
+			archives[key] = artifact
+			release.Platforms = append(release.Platforms, goreleaserPlatform{
+				Os:          artifact.Goos,
+				Arch:        artifact.Goarch,
+				Filename:    artifact.Name,
+				ArchivePath: artifactPath,
+			})
+		case "Checksum":
+			release.ShasumsPath = artifactPath
+			checksumName = artifact.Name
+		case "Signature":
+			signatures[artifact.Name] = artifactPath
+		}
+	}
+	if checksumName != "" {
+		release.ShasumsSigPath = signatures[checksumName+".sig"]
+	}
+
+	if release.Version == "" {
+		return nil, fmt.Errorf("unable to determine the release version from %s", distDir)
+	}
+	if release.ShasumsPath == "" {
+		return nil, fmt.Errorf("the goreleaser artifacts in %s hold no checksum, enable the checksum section", artifactsPath)
+	}
+	if release.ShasumsSigPath == "" {
+		return nil, fmt.Errorf("the goreleaser artifacts in %s hold no %s.sig signature of the checksum, sign the checksum in the signs section", artifactsPath, checksumName)
+	}
+	if len(release.Platforms) == 0 {
+		return nil, fmt.Errorf("the goreleaser artifacts in %s hold no archives", artifactsPath)
+	}
+	sort.Slice(release.Platforms, func(i, j int) bool {
+		return platformKey(release.Platforms[i].Os, release.Platforms[i].Arch) < platformKey(release.Platforms[j].Os, release.Platforms[j].Arch)
+	})
+	return &release, nil
+}
+
+// variant describes the goarm or goamd64 variant of the artifact.
+func (a goreleaserArtifact) variant() string {
+	switch {
+	case a.Goarm != "":
+		return "goarm " + a.Goarm
+	case a.Goamd64 != "":
+		return "goamd64 " + a.Goamd64
+	}
+	return "no variant"
+}
+
+// platformKey is the key of a platform in the platforms map of a release.
+func platformKey(os, arch string) string {
+	return fmt.Sprintf("%s_%s", os, arch)
+}
@@ -0,0 +1,220 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// gpgKeyMetadata describes the primary key of an ASCII-armored public GPG key.
+type gpgKeyMetadata struct {
+	fingerprint string
+	userIds     []string
+	algorithm   string
+	bitLength   int64
+	createdAt   time.Time
+	// expiresAt is nil for keys that do not expire
+	expiresAt *time.Time
+	revoked   bool
+}
+
+// usable returns an error when the key can no longer be used to verify signatures.
+func (k gpgKeyMetadata) usable(now time.Time) error {
+	if k.revoked {
+		return errors.New("the key is revoked")
+	}
+	if k.expiresAt != nil && !k.expiresAt.After(now) {
+		return fmt.Errorf("the key expired at %s", k.expiresAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// parsePublicKey parses an ASCII-armored public key block holding exactly one key.
+func parsePublicKey(armored string) (gpgKeyMetadata, error) {
+	if strings.Count(armored, "-----BEGIN PGP") > 1 {
+		return gpgKeyMetadata{}, errors.New("the value holds more than one armored block, register each key separately")
+	}
+	block, err := armor.Decode(strings.NewReader(armored))
+	if err != nil {
+		return gpgKeyMetadata{}, fmt.Errorf("the value is not ASCII-armored: %w", err)
+	}
+	switch block.Type {
+	case openpgp.PublicKeyType:
+	case openpgp.PrivateKeyType:
+		return gpgKeyMetadata{}, errors.New("the value is a private key, only the public key must be registered")
+	default:
+		return gpgKeyMetadata{}, fmt.Errorf("the armored block is a %s, expected a %s", block.Type, openpgp.PublicKeyType)
+	}
+
+	entities, err := openpgp.ReadKeyRing(block.Body)
+	if err != nil {
+		return gpgKeyMetadata{}, fmt.Errorf("unable to read the public key: %w", err)
+	}
+	if len(entities) != 1 {
+		return gpgKeyMetadata{}, fmt.Errorf("the block holds %d keys, register each key separately", len(entities))
+	}
+	entity := entities[0]
+	if entity.PrivateKey != nil {
+		return gpgKeyMetadata{}, errors.New("the block holds a private key, only the public key must be registered")
+	}
+
+	metadata := gpgKeyMetadata{
+		fingerprint: fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint),
+		algorithm:   publicKeyAlgorithmName(entity.PrimaryKey.PubKeyAlgo),
+		createdAt:   entity.PrimaryKey.CreationTime.UTC(),
+		revoked:     len(entity.Revocations) > 0,
+	}
+	if bitLength, err := entity.PrimaryKey.BitLength(); err == nil {
+		metadata.bitLength = int64(bitLength)
+	}
+
+	// The primary user id goes first, followed by the others in alphabetical order
+	for name := range entity.Identities {
+		metadata.userIds = append(metadata.userIds, name)
+	}
+	if len(metadata.userIds) == 0 {
+		return gpgKeyMetadata{}, errors.New("the key has no user id")
+	}
+	sort.Strings(metadata.userIds)
+	selfSignature := entity.Identities[metadata.userIds[0]].SelfSignature
+	for i, name := range metadata.userIds {
+		identity := entity.Identities[name]
+		if identity.SelfSignature.IsPrimaryId != nil && *identity.SelfSignature.IsPrimaryId {
+			selfSignature = identity.SelfSignature
+			metadata.userIds = append(append([]string{name}, metadata.userIds[:i]...), metadata.userIds[i+1:]...)
+			break
+		}
+	}
+
+	if selfSignature.KeyLifetimeSecs != nil && *selfSignature.KeyLifetimeSecs > 0 {
+		expiresAt := metadata.createdAt.Add(time.Duration(*selfSignature.KeyLifetimeSecs) * time.Second)
+		metadata.expiresAt = &expiresAt
+	}
+	return metadata, nil
+}
+
+// sameGpgKey reports whether two ASCII-armored public keys hold the same key, regardless of whitespace, armor headers or encoding.
+func sameGpgKey(a, b string) bool {
+	if a == b {
+		return true
+	}
+	keyA, err := parsePublicKey(a)
+	if err != nil {
+		return false
+	}
+	keyB, err := parsePublicKey(b)
+	if err != nil {
+		return false
+	}
+	return keyA.fingerprint == keyB.fingerprint
+}
+
+// readSigningKey reads a single ASCII-armored private key, decrypting it with the passphrase when it is encrypted.
+func readSigningKey(armored, passphrase string) (*openpgp.Entity, error) {
+	entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armored))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the private key: %w", err)
+	}
+	if len(entities) != 1 {
+		return nil, fmt.Errorf("the value holds %d keys, expected a single private key", len(entities))
+	}
+	entity := entities[0]
+	if entity.PrivateKey == nil {
+		return nil, errors.New("the value is a public key, signing requires the private key")
+	}
+
+	keys := []*packet.PrivateKey{entity.PrivateKey}
+	for _, subkey := range entity.Subkeys {
+		if subkey.PrivateKey != nil {
+			keys = append(keys, subkey.PrivateKey)
+		}
+	}
+	for _, key := range keys {
+		if !key.Encrypted {
+			continue
+		}
+		if passphrase == "" {
+			return nil, errors.New("the private key is encrypted, a passphrase is required")
+		}
+		if err := key.Decrypt([]byte(passphrase)); err != nil {
+			return nil, errors.New("unable to decrypt the private key, the passphrase is incorrect")
+		}
+	}
+	return entity, nil
+}
+
+// gpgKeyId returns the id TFE registers a key under, the hexadecimal id of its primary key.
+func gpgKeyId(entity *openpgp.Entity) string {
+	return fmt.Sprintf("%016X", entity.PrimaryKey.KeyId)
+}
+
+// publicKeyRequiresReplace requires replacement when the planned public key is a different key than the one in state,
+// unless retain_previous rotates the key in place.
+func publicKeyRequiresReplace() planmodifier.String {
+	return stringplanmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+			var retainPrevious types.Bool
+			diags := req.Plan.GetAttribute(ctx, path.Root("retain_previous"), &retainPrevious)
+			resp.Diagnostics.Append(diags...)
+			if retainPrevious.ValueBool() && !req.PlanValue.IsUnknown() {
+				return
+			}
+			resp.RequiresReplace = req.PlanValue.IsUnknown() || !sameGpgKey(req.StateValue.ValueString(), req.PlanValue.ValueString())
+		},
+		"A different key, by fingerprint, requires replacement unless retain_previous is set. Whitespace, armor header and encoding differences are updated in place",
+		"A different key, by fingerprint, requires replacement unless `retain_previous` is set. Whitespace, armor header and encoding differences are updated in place",
+	)
+}
+
+func publicKeyAlgorithmName(algorithm packet.PublicKeyAlgorithm) string {
+	switch algorithm {
+	case packet.PubKeyAlgoRSA, packet.PubKeyAlgoRSAEncryptOnly, packet.PubKeyAlgoRSASignOnly:
+		return "RSA"
+	case packet.PubKeyAlgoDSA:
+		return "DSA"
+	case packet.PubKeyAlgoElGamal:
+		return "ElGamal"
+	case packet.PubKeyAlgoECDH:
+		return "ECDH"
+	case packet.PubKeyAlgoECDSA:
+		return "ECDSA"
+	}
+	return fmt.Sprintf("unknown (%d)", algorithm)
+}
+
+// armoredPublicKeyValidator validates that a string is a single, valid, ASCII-armored public GPG key.
+// Whether the key expired depends on the time of the plan, so it is checked on plan rather than on validation.
+type armoredPublicKeyValidator struct{}
+
+func (v armoredPublicKeyValidator) Description(_ context.Context) string {
+	return "value must be a single ASCII-armored public GPG key"
+}
+
+func (v armoredPublicKeyValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v armoredPublicKeyValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if _, err := parsePublicKey(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid GPG public key",
+			"The public key cannot be registered: "+err.Error(),
+		)
+	}
+}
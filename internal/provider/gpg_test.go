@@ -0,0 +1,261 @@
+package provider_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	m "github.com/tsanton/terraform-provider-tfepatch/provider/models"
+)
+
+func Test_gpg_key_public_key_validation(t *testing.T) {
+	/* Arrange */
+	key := newTestGpgEntity(t, time.Now(), 0)
+	other := newTestGpgEntity(t, time.Now(), 0)
+	expired := newTestGpgEntity(t, time.Now().Add(-48*time.Hour), uint32(time.Hour/time.Second))
+	expiring := newTestGpgEntity(t, time.Now(), uint32(time.Hour/time.Second))
+
+	tests := []struct {
+		name      string
+		publicKey string
+		err       string
+	}{
+		{name: "public key", publicKey: armoredGpgKeys(t, openpgp.PublicKeyType, key)},
+		{name: "expiring public key", publicKey: armoredGpgKeys(t, openpgp.PublicKeyType, expiring)},
+		{name: "private key", publicKey: armoredGpgKeys(t, openpgp.PrivateKeyType, key), err: "private key"},
+		// Expiry depends on the time of the plan, so it is checked on plan
+		{name: "expired key", publicKey: armoredGpgKeys(t, openpgp.PublicKeyType, expired)},
+		{name: "multiple keys", publicKey: armoredGpgKeys(t, openpgp.PublicKeyType, key, other), err: "2 keys"},
+		{name: "multiple blocks", publicKey: armoredGpgKeys(t, openpgp.PublicKeyType, key) + "\n" + armoredGpgKeys(t, openpgp.PublicKeyType, other), err: "more than one"},
+		{name: "not armored", publicKey: "ssh-rsa AAAAB3NzaC1yc2E", err: "not ASCII-armored"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* Act */
+			resp := validatePublicKey(t, tt.publicKey)
+
+			/* Assert */
+			if tt.err == "" {
+				assert.False(t, resp.Diagnostics.HasError())
+				return
+			}
+			assert.True(t, resp.Diagnostics.HasError())
+			assert.Contains(t, resp.Diagnostics.Errors()[0].Detail(), tt.err)
+		})
+	}
+}
+
+func Test_gpg_key_plan_public_key_metadata(t *testing.T) {
+	/* Arrange */
+	created := time.Now().Add(-time.Hour).Truncate(time.Second)
+	entity := newTestGpgEntity(t, created, uint32(24*time.Hour/time.Second))
+	server := newTestApiServer(t, notFoundHandler)
+	r := newTestResource(t, "tfepatch_gpg_key", testProviderValues(server.URL))
+	req := newTestModifyPlanRequest(t, r, map[string]tftypes.Value{
+		"namespace":  tftypes.NewValue(tftypes.String, "test-org"),
+		"public_key": tftypes.NewValue(tftypes.String, armoredGpgKeys(t, openpgp.PublicKeyType, entity)),
+	}, nil)
+	resp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+
+	/* Act */
+	r.(fwresource.ResourceWithModifyPlan).ModifyPlan(context.Background(), req, &resp)
+
+	/* Assert */
+	assert.False(t, resp.Diagnostics.HasError())
+	var plan m.GpgKey
+	resp.Plan.Get(context.Background(), &plan)
+	assert.Equal(t, fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint), plan.Fingerprint.ValueString())
+	assert.Equal(t, "RSA", plan.Algorithm.ValueString())
+	assert.Equal(t, int64(2048), plan.BitLength.ValueInt64())
+	assert.Equal(t, created.UTC().Format(time.RFC3339), plan.KeyCreatedAt.ValueString())
+	assert.Equal(t, created.Add(24*time.Hour).UTC().Format(time.RFC3339), plan.KeyExpiresAt.ValueString())
+	assert.Equal(t, types.ListValueMust(types.StringType, []attr.Value{
+		types.StringValue("Gruntwork (Integration test GPG key) <donotreply@gruntwork.com>"),
+	}), plan.UserIds)
+	assert.True(t, plan.KeyId.IsUnknown())
+}
+
+func Test_gpg_key_plan_expired_key(t *testing.T) {
+	expired := armoredGpgKeys(t, openpgp.PublicKeyType, newTestGpgEntity(t, time.Now().Add(-48*time.Hour), uint32(time.Hour/time.Second)))
+	registered := armoredGpgKeys(t, openpgp.PublicKeyType, newTestGpgEntity(t, time.Now(), 0))
+	tests := []struct {
+		name    string
+		prior   string
+		err     string
+		warning string
+	}{
+		{name: "create", err: "Invalid GPG public key"},
+		{name: "rotation", prior: registered, err: "Invalid GPG public key"},
+		{name: "registered key", prior: expired, warning: "Unusable GPG public key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* Arrange */
+			server := newTestApiServer(t, notFoundHandler)
+			r := newTestResource(t, "tfepatch_gpg_key", testProviderValues(server.URL))
+			var prior any
+			if tt.prior != "" {
+				prior = &m.GpgKey{
+					Id:             types.StringValue("test-org||32966F3FB5AC1129"),
+					Organization:   types.StringValue("test-org"),
+					Namespace:      types.StringValue("test-org"),
+					PublicKey:      types.StringValue(tt.prior),
+					KeyId:          types.StringValue("32966F3FB5AC1129"),
+					UserIds:        types.ListNull(types.StringType),
+					RetainedKeyIds: types.ListNull(types.StringType),
+				}
+			}
+			req := newTestModifyPlanRequest(t, r, map[string]tftypes.Value{
+				"namespace":  tftypes.NewValue(tftypes.String, "test-org"),
+				"public_key": tftypes.NewValue(tftypes.String, expired),
+			}, prior)
+			resp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+
+			/* Act */
+			r.(fwresource.ResourceWithModifyPlan).ModifyPlan(context.Background(), req, &resp)
+
+			/* Assert */
+			if tt.err != "" {
+				assert.Equal(t, 1, resp.Diagnostics.ErrorsCount())
+				assert.Equal(t, tt.err, resp.Diagnostics.Errors()[0].Summary())
+				assert.Contains(t, resp.Diagnostics.Errors()[0].Detail(), "expired")
+				return
+			}
+			assert.False(t, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+			assert.Equal(t, 1, resp.Diagnostics.WarningsCount())
+			assert.Equal(t, tt.warning, resp.Diagnostics.Warnings()[0].Summary())
+		})
+	}
+}
+
+// validatePublicKey runs the validators of the public_key attribute of tfepatch_gpg_key.
+func validatePublicKey(t *testing.T, publicKey string) *validator.StringResponse {
+	server := newTestApiServer(t, notFoundHandler)
+	r := newTestResource(t, "tfepatch_gpg_key", testProviderValues(server.URL))
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(context.Background(), fwresource.SchemaRequest{}, &schemaResp)
+	resp := &validator.StringResponse{}
+	for _, v := range schemaResp.Schema.Attributes["public_key"].(schema.StringAttribute).Validators {
+		v.ValidateString(context.Background(), validator.StringRequest{
+			Path:        path.Root("public_key"),
+			ConfigValue: types.StringValue(publicKey),
+		}, resp)
+	}
+	return resp
+}
+
+// newTestGpgEntity generates a 2048 bit RSA key created at the given time, expiring after lifetime seconds unless zero.
+func newTestGpgEntity(t *testing.T, created time.Time, lifetime uint32) *openpgp.Entity {
+	config := &packet.Config{RSABits: 2048, Time: func() time.Time { return created }}
+	entity, err := openpgp.NewEntity("Gruntwork", "Integration test GPG key", "donotreply@gruntwork.com", config)
+	assert.Nil(t, err)
+	if lifetime > 0 {
+		for name, identity := range entity.Identities {
+			identity.SelfSignature.KeyLifetimeSecs = &lifetime
+			assert.Nil(t, identity.SelfSignature.SignUserId(name, entity.PrimaryKey, entity.PrivateKey, config))
+		}
+	}
+	return entity
+}
+
+// armoredGpgKeys armors the keys of the entities in a single block of the block type.
+func armoredGpgKeys(t *testing.T, blockType string, entities ...*openpgp.Entity) string {
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, blockType, nil)
+	assert.Nil(t, err)
+	for _, entity := range entities {
+		if blockType == openpgp.PrivateKeyType {
+			assert.Nil(t, entity.SerializePrivate(w, nil))
+		} else {
+			assert.Nil(t, entity.Serialize(w))
+		}
+	}
+	assert.Nil(t, w.Close())
+	return buf.String()
+}
+
+func Test_gpg_key_public_key_requires_replace(t *testing.T) {
+	/* Arrange */
+	key := newTestGpgEntity(t, time.Now(), 0)
+	other := newTestGpgEntity(t, time.Now(), 0)
+	armored := armoredGpgKeys(t, openpgp.PublicKeyType, key)
+
+	tests := []struct {
+		name            string
+		planned         types.String
+		retainPrevious  bool
+		requiresReplace bool
+	}{
+		{name: "same armor", planned: types.StringValue(armored), requiresReplace: false},
+		{name: "re-encoded key", planned: types.StringValue(reencodedGpgKey(armored)), requiresReplace: false},
+		{name: "different key", planned: types.StringValue(armoredGpgKeys(t, openpgp.PublicKeyType, other)), requiresReplace: true},
+		{name: "unknown key", planned: types.StringUnknown(), requiresReplace: true},
+		{name: "different key retaining previous", planned: types.StringValue(armoredGpgKeys(t, openpgp.PublicKeyType, other)), retainPrevious: true, requiresReplace: false},
+		{name: "unknown key retaining previous", planned: types.StringUnknown(), retainPrevious: true, requiresReplace: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* Act */
+			resp := planPublicKey(t, types.StringValue(armored), tt.planned, tt.retainPrevious)
+
+			/* Assert */
+			assert.False(t, resp.Diagnostics.HasError())
+			assert.Equal(t, tt.requiresReplace, resp.RequiresReplace)
+		})
+	}
+}
+
+// planPublicKey runs the plan modifiers of the public_key attribute of tfepatch_gpg_key for an update.
+func planPublicKey(t *testing.T, prior, planned types.String, retainPrevious bool) *planmodifier.StringResponse {
+	ctx := context.Background()
+	server := newTestApiServer(t, notFoundHandler)
+	r := newTestResource(t, "tfepatch_gpg_key", testProviderValues(server.URL))
+	state := newTestState(t, r, &m.GpgKey{
+		Id:             types.StringValue("test-org||32966f3fb5ac1129"),
+		Organization:   types.StringValue("test-org"),
+		Namespace:      types.StringValue("test-org"),
+		PublicKey:      prior,
+		KeyId:          types.StringValue("32966F3FB5AC1129"),
+		UserIds:        types.ListNull(types.StringType),
+		RetainedKeyIds: types.ListNull(types.StringType),
+	})
+	planReq := newTestModifyPlanRequest(t, r, map[string]tftypes.Value{
+		"namespace":       tftypes.NewValue(tftypes.String, "test-org"),
+		"public_key":      tftypes.NewValue(tftypes.String, planned.ValueString()),
+		"retain_previous": tftypes.NewValue(tftypes.Bool, retainPrevious),
+	}, nil)
+
+	resp := &planmodifier.StringResponse{PlanValue: planned}
+	for _, modifier := range state.Schema.GetAttributes()["public_key"].(schema.StringAttribute).PlanModifiers {
+		modifier.PlanModifyString(ctx, planmodifier.StringRequest{
+			Path:        path.Root("public_key"),
+			Config:      planReq.Config,
+			ConfigValue: planned,
+			Plan:        planReq.Plan,
+			PlanValue:   planned,
+			State:       state,
+			StateValue:  prior,
+		}, resp)
+	}
+	return resp
+}
+
+// reencodedGpgKey adds an armor header and trailing whitespace the way gpg and TFE may re-encode a key.
+func reencodedGpgKey(armored string) string {
+	return strings.Replace(armored, "-----\n\n", "-----\nVersion: GnuPG v2\n\n", 1) + "\n\n"
+}
@@ -0,0 +1,219 @@
+package provider_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	p "github.com/tsanton/terraform-provider-tfepatch/provider"
+)
+
+// newTestApiServer starts a local server standing in for the TFE API that is closed when the test completes.
+func newTestApiServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// notFoundHandler answers every request the way TFE answers a request for a deleted object.
+func notFoundHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	w.WriteHeader(http.StatusNotFound)
+	_, _ = w.Write([]byte(`{"errors":[{"status":"404","title":"not found"}]}`))
+}
+
+// testProviderValues returns the provider configuration pointing at a local test server, without retries.
+func testProviderValues(hostname string) map[string]tftypes.Value {
+	return map[string]tftypes.Value{
+		"hostname":     tftypes.NewValue(tftypes.String, hostname),
+		"token":        tftypes.NewValue(tftypes.String, "test-token"),
+		"organization": tftypes.NewValue(tftypes.String, "test-org"),
+		"max_retries":  tftypes.NewValue(tftypes.Number, 0),
+	}
+}
+
+// newTestResource returns the resource registered under typeName, configured through the provider with the given attribute values.
+// Unset provider attributes are null.
+func newTestResource(t *testing.T, typeName string, providerValues map[string]tftypes.Value) resource.Resource {
+	ctx := context.Background()
+	prov := p.New()()
+	configureResp := configureTestProvider(prov, providerValues)
+	if configureResp.Diagnostics.HasError() {
+		t.Fatalf("unable to configure provider: %v", configureResp.Diagnostics)
+	}
+
+	var metadataResp provider.MetadataResponse
+	prov.Metadata(ctx, provider.MetadataRequest{}, &metadataResp)
+	for _, factory := range prov.Resources(ctx) {
+		r := factory()
+		var resp resource.MetadataResponse
+		r.Metadata(ctx, resource.MetadataRequest{ProviderTypeName: metadataResp.TypeName}, &resp)
+		if resp.TypeName != typeName {
+			continue
+		}
+		if rc, ok := r.(resource.ResourceWithConfigure); ok {
+			rc.Configure(ctx, resource.ConfigureRequest{ProviderData: configureResp.ResourceData}, &resource.ConfigureResponse{})
+		}
+		return r
+	}
+	t.Fatalf("resource %s is not registered", typeName)
+	return nil
+}
+
+// newTestDataSource returns the data source registered under typeName, configured through the provider with the given attribute values.
+func newTestDataSource(t *testing.T, typeName string, providerValues map[string]tftypes.Value) datasource.DataSource {
+	ctx := context.Background()
+	prov := p.New()()
+	configureResp := configureTestProvider(prov, providerValues)
+	if configureResp.Diagnostics.HasError() {
+		t.Fatalf("unable to configure provider: %v", configureResp.Diagnostics)
+	}
+
+	var metadataResp provider.MetadataResponse
+	prov.Metadata(ctx, provider.MetadataRequest{}, &metadataResp)
+	for _, factory := range prov.DataSources(ctx) {
+		d := factory()
+		var resp datasource.MetadataResponse
+		d.Metadata(ctx, datasource.MetadataRequest{ProviderTypeName: metadataResp.TypeName}, &resp)
+		if resp.TypeName != typeName {
+			continue
+		}
+		if dc, ok := d.(datasource.DataSourceWithConfigure); ok {
+			dc.Configure(ctx, datasource.ConfigureRequest{ProviderData: configureResp.DataSourceData}, &datasource.ConfigureResponse{})
+		}
+		return d
+	}
+	t.Fatalf("data source %s is not registered", typeName)
+	return nil
+}
+
+// readTestDataSource reads the data source with the configuration values. Unset attributes are null.
+func readTestDataSource(d datasource.DataSource, configValues map[string]tftypes.Value) datasource.ReadResponse {
+	ctx := context.Background()
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	typ := schemaResp.Schema.Type().TerraformType(ctx)
+	resp := datasource.ReadResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(typ, nil)}}
+	d.Read(ctx, datasource.ReadRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: objectValue(typ, configValues)}}, &resp)
+	return resp
+}
+
+// configureTestProvider configures the provider with the given attribute values. Unset provider attributes are null.
+func configureTestProvider(prov provider.Provider, providerValues map[string]tftypes.Value) provider.ConfigureResponse {
+	ctx := context.Background()
+	var schemaResp provider.SchemaResponse
+	prov.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+	config := tfsdk.Config{
+		Schema: schemaResp.Schema,
+		Raw:    objectValue(schemaResp.Schema.Type().TerraformType(ctx), providerValues),
+	}
+	var resp provider.ConfigureResponse
+	prov.Configure(ctx, provider.ConfigureRequest{Config: config}, &resp)
+	return resp
+}
+
+// newTestState returns the state of the resource holding the model.
+func newTestState(t *testing.T, r resource.Resource, model any) tfsdk.State {
+	ctx := context.Background()
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	state := tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}
+	// The zero timeouts value has no attribute types, so models without timeouts get a null timeouts block
+	if field := reflect.ValueOf(model).Elem().FieldByName("Timeouts"); field.IsValid() && field.Interface().(timeouts.Value).IsNull() {
+		typ := schemaResp.Schema.Blocks["timeouts"].Type()
+		value, err := typ.ValueFromTerraform(ctx, tftypes.NewValue(typ.TerraformType(ctx), nil))
+		if err != nil {
+			t.Fatalf("unable to build the null timeouts: %v", err)
+		}
+		field.Set(reflect.ValueOf(value))
+	}
+	if diags := state.Set(ctx, model); diags.HasError() {
+		t.Fatalf("unable to set state: %v", diags)
+	}
+	return state
+}
+
+// importTestState imports the resource by id into an empty state.
+func importTestState(r resource.Resource, id string) resource.ImportStateResponse {
+	ctx := context.Background()
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	resp := resource.ImportStateResponse{State: tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}}
+	r.(resource.ResourceWithImportState).ImportState(ctx, resource.ImportStateRequest{ID: id}, &resp)
+	return resp
+}
+
+// objectValue builds an object of the given type, defaulting attributes that are not in values to null.
+func objectValue(typ tftypes.Type, values map[string]tftypes.Value) tftypes.Value {
+	object := typ.(tftypes.Object)
+	attributes := map[string]tftypes.Value{}
+	for name, attributeType := range object.AttributeTypes {
+		if value, ok := values[name]; ok {
+			attributes[name] = value
+		} else {
+			attributes[name] = tftypes.NewValue(attributeType, nil)
+		}
+	}
+	return tftypes.NewValue(object, attributes)
+}
+
+// newTestModifyPlanRequest returns the plan request of the resource for the configuration values and the prior state model, nil on create.
+// As proposed by Terraform, computed attributes that are null in the configuration are unknown in the plan.
+func newTestModifyPlanRequest(t *testing.T, r resource.Resource, configValues map[string]tftypes.Value, prior any) resource.ModifyPlanRequest {
+	ctx := context.Background()
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	typ := schemaResp.Schema.Type().TerraformType(ctx)
+
+	planValues := map[string]tftypes.Value{}
+	for name, attribute := range schemaResp.Schema.Attributes {
+		attributeType := attribute.GetType().TerraformType(ctx)
+		if value, ok := configValues[name]; ok {
+			planValues[name] = value
+		} else if attribute.IsComputed() {
+			planValues[name] = tftypes.NewValue(attributeType, tftypes.UnknownValue)
+		}
+	}
+	for name := range schemaResp.Schema.Blocks {
+		if value, ok := configValues[name]; ok {
+			planValues[name] = value
+		}
+	}
+
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(typ, nil)}
+	if prior != nil {
+		state = newTestState(t, r, prior)
+	}
+	return resource.ModifyPlanRequest{
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: objectValue(typ, configValues)},
+		Plan:   tfsdk.Plan{Schema: schemaResp.Schema, Raw: objectValue(typ, planValues)},
+		State:  state,
+	}
+}
+
+// newTestTlsApiServer starts a local TLS server standing in for the TFE API. Client certificates are required when clientCAs is set.
+func newTestTlsApiServer(t *testing.T, handler http.HandlerFunc, clientCAs *x509.CertPool) *httptest.Server {
+	server := httptest.NewUnstartedServer(handler)
+	if clientCAs != nil {
+		server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// stringValueOrNull maps the empty strings the API returns for absent links to a null value.
+func stringValueOrNull(value string) types.String {
+	if value == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(value)
+}
+
+// envString returns the value of the environment variable, or the fallback when it is unset or empty.
+func envString(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// envBool parses the value of the environment variable as strconv.ParseBool does, i.e. 1, t, TRUE or false.
+// The fallback is returned when the variable is unset or empty.
+func envBool(key string, fallback bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	return strconv.ParseBool(value)
+}
@@ -0,0 +1,17 @@
+package provider
+
+import "strings"
+
+// splitImportId splits an import id on the separator, reporting whether it holds exactly n non-empty parts.
+func splitImportId(id, separator string, n int) ([]string, bool) {
+	parts := strings.Split(id, separator)
+	if len(parts) != n {
+		return nil, false
+	}
+	for _, part := range parts {
+		if strings.TrimSpace(part) == "" {
+			return nil, false
+		}
+	}
+	return parts, true
+}
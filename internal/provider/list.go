@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	api "github.com/tsanton/tfe-client/tfe"
+	mresp "github.com/tsanton/tfe-client/tfe/models/response"
+)
+
+const listPageSize = 100
+
+// listRegistryProviderVersions returns every version of a private registry provider.
+// ProviderVersionService.List only returns the first page, so the pages are requested through the client directly.
+func listRegistryProviderVersions(ctx context.Context, client *api.TerraformEnterpriseClient, organization, namespace, name string) ([]mresp.ProviderVersionData, error) {
+	versions := []mresp.ProviderVersionData{}
+	for page := 1; ; page++ {
+		path := fmt.Sprintf("/api/v2/organizations/%s/registry-providers/private/%s/%s/versions?%s", organization, namespace, name, pageQuery(page))
+		resp, err := api.MakeRequest[interface{}, mresp.ProviderVersions](ctx, client, http.MethodGet, http.StatusOK, path, nil)
+		if err != nil {
+			return nil, err
+		}
+		if resp == nil {
+			return versions, nil
+		}
+		versions = append(versions, resp.Data...)
+		if resp.Meta.Pagination.NextPage == nil {
+			return versions, nil
+		}
+	}
+}
+
+// listGpgKeys returns every GPG key of the namespaces.
+// GpgService.List only returns the first page, so the pages are requested through the client directly.
+func listGpgKeys(ctx context.Context, client *api.TerraformEnterpriseClient, namespaces []string) ([]mresp.GpgKeyData, error) {
+	keys := []mresp.GpgKeyData{}
+	for page := 1; ; page++ {
+		query := pageQuery(page) + "&" + url.Values{"filter[namespace]": []string{strings.Join(namespaces, ",")}}.Encode()
+		resp, err := api.MakeRequest[interface{}, mresp.GpgKeys](ctx, client, http.MethodGet, http.StatusOK, "/api/registry/private/v2/gpg-keys?"+query, nil)
+		if err != nil {
+			return nil, err
+		}
+		if resp == nil {
+			return keys, nil
+		}
+		keys = append(keys, resp.Data...)
+		if resp.Meta.Pagination.NextPage == nil {
+			return keys, nil
+		}
+	}
+}
+
+// registryProviders is a page of registry providers, which the TFE client has no list for.
+type registryProviders struct {
+	Data []mresp.ProviderData `json:"data"`
+	Meta mresp.ListMeta       `json:"meta"`
+}
+
+// listReferencedKeyIds returns the upper cased key ids that the versions of the private providers in the namespace are signed with.
+func listReferencedKeyIds(ctx context.Context, client *api.TerraformEnterpriseClient, organization, namespace string) (map[string]bool, error) {
+	referenced := map[string]bool{}
+	for page := 1; ; page++ {
+		query := pageQuery(page) + "&" + url.Values{"filter[registry_name]": []string{"private"}}.Encode()
+		resp, err := api.MakeRequest[interface{}, registryProviders](ctx, client, http.MethodGet, http.StatusOK, fmt.Sprintf("/api/v2/organizations/%s/registry-providers?%s", organization, query), nil)
+		if err != nil {
+			return nil, err
+		}
+		if resp == nil {
+			return referenced, nil
+		}
+		for _, provider := range resp.Data {
+			if !strings.EqualFold(provider.Attributes.Namespace, namespace) {
+				continue
+			}
+			versions, err := listRegistryProviderVersions(ctx, client, organization, provider.Attributes.Namespace, provider.Attributes.Name)
+			if err != nil {
+				return nil, err
+			}
+			for _, version := range versions {
+				referenced[strings.ToUpper(version.Attributes.KeyId)] = true
+			}
+		}
+		if resp.Meta.Pagination.NextPage == nil {
+			return referenced, nil
+		}
+	}
+}
+
+func pageQuery(page int) string {
+	return url.Values{
+		"page[number]": []string{strconv.Itoa(page)},
+		"page[size]":   []string{strconv.Itoa(listPageSize)},
+	}.Encode()
+}
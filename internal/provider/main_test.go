@@ -1,11 +1,12 @@
 package provider_test
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
 
-	log "github.com/sirupsen/logrus"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
 
 	u "github.com/tsanton/terraform-provider-tfepatch/utilities"
 	api "github.com/tsanton/tfe-client/tfe"
@@ -17,23 +18,39 @@ var cli *api.TerraformEnterpriseClient
 var logger u.ILogger
 
 func TestMain(m *testing.M) {
-	logger = log.New()
+	logger = u.NewTflogLogger(tflogtest.RootLogger(context.Background(), os.Stderr), "tests")
 
 	logger.Info("Test suite setup")
 
+	hostname := "https://app.terraform.io"
 	tfeToken := u.GetEnv("TFE_TOKEN", "")
+	if mode := u.GetEnv(cassetteModeEnv, ""); mode != "" {
+		var err error
+		cassettes, err = newCassetteProxy(mode, hostname, tfeToken, u.GetEnv("TFE_ORG_NAME", ""))
+		if err != nil {
+			panic("unable to create the cassette proxy: " + err.Error())
+		}
+		hostname = cassettes.server.URL
+		if mode == cassetteModeReplay {
+			tfeToken = cassetteToken
+			os.Setenv("TFE_ORG_NAME", cassetteOrganization)
+			os.Setenv("TF_ACC", "1")
+		}
+		logger.Infof("Running the acceptance tests in %s mode", mode)
+	}
+
 	providerConfig = fmt.Sprintf(`
 provider "tfepatch" {
-	hostname  		= "https://app.terraform.io"
+	hostname  		= "%s"
 	token 			= "%s"
 	organization	= "%s"
 	ssl_skip_verify = false
 
-}`, tfeToken, u.GetEnv("TFE_ORG_NAME", ""))
+}`, hostname, tfeToken, u.GetEnv("TFE_ORG_NAME", ""))
 
 	var err error
 	cli, err = api.NewClient(logger, &apim.ClientConfig{
-		Address: "https://app.terraform.io",
+		Address: hostname,
 		Token:   tfeToken,
 	})
 	if err != nil {
@@ -44,6 +61,9 @@ provider "tfepatch" {
 	exitVal := m.Run()
 
 	logger.Info("Test suite teardown")
+	if cassettes != nil {
+		cassettes.server.Close()
+	}
 
 	os.Exit(exitVal)
 }
@@ -1,11 +1,24 @@
 package models
 
-import "github.com/hashicorp/terraform-plugin-framework/types"
+import (
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
 
 type GpgKey struct {
-	Id           types.String `tfsdk:"id"`
-	Organization types.String `tfsdk:"organization"`
-	Namespace    types.String `tfsdk:"namespace"`
-	PublicKey    types.String `tfsdk:"public_key"`
-	KeyId        types.String `tfsdk:"key_id"`
+	Id             types.String   `tfsdk:"id"`
+	Organization   types.String   `tfsdk:"organization"`
+	Namespace      types.String   `tfsdk:"namespace"`
+	PublicKey      types.String   `tfsdk:"public_key"`
+	KeyId          types.String   `tfsdk:"key_id"`
+	Fingerprint    types.String   `tfsdk:"fingerprint"`
+	UserIds        types.List     `tfsdk:"user_ids"`
+	Algorithm      types.String   `tfsdk:"algorithm"`
+	BitLength      types.Int64    `tfsdk:"bit_length"`
+	KeyCreatedAt   types.String   `tfsdk:"key_created_at"`
+	KeyExpiresAt   types.String   `tfsdk:"key_expires_at"`
+	RetainPrevious types.Bool     `tfsdk:"retain_previous"`
+	RetainedKeyIds types.List     `tfsdk:"retained_key_ids"`
+	AdoptExisting  types.Bool     `tfsdk:"adopt_existing"`
+	Timeouts       timeouts.Value `tfsdk:"timeouts"`
 }
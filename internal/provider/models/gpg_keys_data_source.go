@@ -0,0 +1,19 @@
+package models
+
+import "github.com/hashicorp/terraform-plugin-framework/types"
+
+type GpgKeysDataSource struct {
+	Id          types.String           `tfsdk:"id"`
+	Namespaces  []types.String         `tfsdk:"namespaces"`
+	KeyIdPrefix types.String           `tfsdk:"key_id_prefix"`
+	Keys        []GpgKeysDataSourceKey `tfsdk:"keys"`
+}
+
+type GpgKeysDataSourceKey struct {
+	Namespace  types.String `tfsdk:"namespace"`
+	KeyId      types.String `tfsdk:"key_id"`
+	AsciiArmor types.String `tfsdk:"ascii_armor"`
+	Source     types.String `tfsdk:"source"`
+	CreatedAt  types.String `tfsdk:"created_at"`
+	UpdatedAt  types.String `tfsdk:"updated_at"`
+}
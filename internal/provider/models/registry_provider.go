@@ -1,11 +1,18 @@
 package models
 
-import "github.com/hashicorp/terraform-plugin-framework/types"
+import (
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
 
 type RegistryProvider struct {
-	Id           types.String `tfsdk:"id"`
-	Organization types.String `tfsdk:"organization"`
-	Namespace    types.String `tfsdk:"namespace"`
-	Name         types.String `tfsdk:"name"`
-	RegistryName types.String `tfsdk:"registry_name"`
+	Id                 types.String   `tfsdk:"id"`
+	Organization       types.String   `tfsdk:"organization"`
+	Namespace          types.String   `tfsdk:"namespace"`
+	Name               types.String   `tfsdk:"name"`
+	RegistryName       types.String   `tfsdk:"registry_name"`
+	DeletionProtection types.Bool     `tfsdk:"deletion_protection"`
+	ForceDestroy       types.Bool     `tfsdk:"force_destroy"`
+	AdoptExisting      types.Bool     `tfsdk:"adopt_existing"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
 }
@@ -0,0 +1,24 @@
+package models
+
+import "github.com/hashicorp/terraform-plugin-framework/types"
+
+type RegistryProviderDataSource struct {
+	Id           types.String                        `tfsdk:"id"`
+	Organization types.String                        `tfsdk:"organization"`
+	Namespace    types.String                        `tfsdk:"namespace"`
+	Name         types.String                        `tfsdk:"name"`
+	RegistryName types.String                        `tfsdk:"registry_name"`
+	CreatedAt    types.String                        `tfsdk:"created_at"`
+	UpdatedAt    types.String                        `tfsdk:"updated_at"`
+	CanDelete    types.Bool                          `tfsdk:"can_delete"`
+	Versions     []RegistryProviderDataSourceVersion `tfsdk:"versions"`
+}
+
+type RegistryProviderDataSourceVersion struct {
+	Version            types.String   `tfsdk:"version"`
+	KeyId              types.String   `tfsdk:"key_id"`
+	Protocols          []types.String `tfsdk:"protocols"`
+	ShasumsUploaded    types.Bool     `tfsdk:"shasums_uploaded"`
+	ShasumsSigUploaded types.Bool     `tfsdk:"shasums_sig_uploaded"`
+	CreatedAt          types.String   `tfsdk:"created_at"`
+}
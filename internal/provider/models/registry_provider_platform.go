@@ -0,0 +1,22 @@
+package models
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type RegistryProviderPlatform struct {
+	Id                      types.String   `tfsdk:"id"`
+	Organization            types.String   `tfsdk:"organization"`
+	Namespace               types.String   `tfsdk:"namespace"`
+	Name                    types.String   `tfsdk:"name"`
+	Version                 types.String   `tfsdk:"version"`
+	Os                      types.String   `tfsdk:"os"`
+	Arch                    types.String   `tfsdk:"arch"`
+	Filename                types.String   `tfsdk:"filename"`
+	ArchivePath             types.String   `tfsdk:"archive_path"`
+	Shasum                  types.String   `tfsdk:"shasum"`
+	ProviderBinaryUploaded  types.Bool     `tfsdk:"provider_binary_uploaded"`
+	ProviderBinaryUploadUrl types.String   `tfsdk:"provider_binary_upload_url"`
+	Timeouts                timeouts.Value `tfsdk:"timeouts"`
+}
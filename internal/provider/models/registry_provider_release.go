@@ -0,0 +1,31 @@
+package models
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type RegistryProviderRelease struct {
+	Id                 types.String   `tfsdk:"id"`
+	Organization       types.String   `tfsdk:"organization"`
+	Namespace          types.String   `tfsdk:"namespace"`
+	Name               types.String   `tfsdk:"name"`
+	Version            types.String   `tfsdk:"version"`
+	KeyId              types.String   `tfsdk:"key_id"`
+	Protocols          types.List     `tfsdk:"protocols"`
+	DistDir            types.String   `tfsdk:"dist_dir"`
+	ArtifactsPath      types.String   `tfsdk:"artifacts_path"`
+	ShasumsSha256      types.String   `tfsdk:"shasums_sha256"`
+	ShasumsUploaded    types.Bool     `tfsdk:"shasums_uploaded"`
+	ShasumsSigUploaded types.Bool     `tfsdk:"shasums_sig_uploaded"`
+	Platforms          types.Map      `tfsdk:"platforms"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
+}
+
+type RegistryProviderReleasePlatform struct {
+	Os                     types.String `tfsdk:"os"`
+	Arch                   types.String `tfsdk:"arch"`
+	Filename               types.String `tfsdk:"filename"`
+	Shasum                 types.String `tfsdk:"shasum"`
+	ProviderBinaryUploaded types.Bool   `tfsdk:"provider_binary_uploaded"`
+}
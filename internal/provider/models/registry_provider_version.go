@@ -0,0 +1,25 @@
+package models
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type RegistryProviderVersion struct {
+	Id                    types.String   `tfsdk:"id"`
+	Organization          types.String   `tfsdk:"organization"`
+	Namespace             types.String   `tfsdk:"namespace"`
+	Name                  types.String   `tfsdk:"name"`
+	Version               types.String   `tfsdk:"version"`
+	KeyId                 types.String   `tfsdk:"key_id"`
+	Protocols             types.List     `tfsdk:"protocols"`
+	ShasumsPath           types.String   `tfsdk:"shasums_path"`
+	ShasumsSigPath        types.String   `tfsdk:"shasums_sig_path"`
+	ShasumsUploaded       types.Bool     `tfsdk:"shasums_uploaded"`
+	ShasumsSigUploaded    types.Bool     `tfsdk:"shasums_sig_uploaded"`
+	ShasumsUploadUrl      types.String   `tfsdk:"shasums_upload_url"`
+	ShasumsSigUploadUrl   types.String   `tfsdk:"shasums_sig_upload_url"`
+	ShasumsDownloadUrl    types.String   `tfsdk:"shasums_download_url"`
+	ShasumsSigDownloadUrl types.String   `tfsdk:"shasums_sig_download_url"`
+	Timeouts              timeouts.Value `tfsdk:"timeouts"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "github.com/hashicorp/terraform-plugin-framework/types"
+
+type ShasumsSignature struct {
+	Id            types.String `tfsdk:"id"`
+	KeyId         types.String `tfsdk:"key_id"`
+	PrivateKey    types.String `tfsdk:"private_key"`
+	Passphrase    types.String `tfsdk:"passphrase"`
+	Shasums       types.String `tfsdk:"shasums"`
+	ShasumsPath   types.String `tfsdk:"shasums_path"`
+	SignaturePath types.String `tfsdk:"signature_path"`
+	ShasumsSha256 types.String `tfsdk:"shasums_sha256"`
+	Signature     types.String `tfsdk:"signature"`
+}
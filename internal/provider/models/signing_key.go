@@ -0,0 +1,24 @@
+package models
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type SigningKey struct {
+	Id           types.String   `tfsdk:"id"`
+	Organization types.String   `tfsdk:"organization"`
+	Namespace    types.String   `tfsdk:"namespace"`
+	Name         types.String   `tfsdk:"name"`
+	Email        types.String   `tfsdk:"email"`
+	Comment      types.String   `tfsdk:"comment"`
+	ExpiryDays   types.Int64    `tfsdk:"expiry_days"`
+	KeyId        types.String   `tfsdk:"key_id"`
+	Fingerprint  types.String   `tfsdk:"fingerprint"`
+	PublicKey    types.String   `tfsdk:"public_key"`
+	PrivateKey   types.String   `tfsdk:"private_key"`
+	Passphrase   types.String   `tfsdk:"passphrase"`
+	KeyCreatedAt types.String   `tfsdk:"key_created_at"`
+	KeyExpiresAt types.String   `tfsdk:"key_expires_at"`
+	Timeouts     timeouts.Value `tfsdk:"timeouts"`
+}
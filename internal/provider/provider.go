@@ -2,28 +2,30 @@ package provider
 
 import (
 	"context"
-	"crypto/tls"
-	"net/http"
+	"fmt"
+	"time"
 
-	"github.com/hashicorp/go-cleanhttp"
 	tfeclient "github.com/tsanton/tfe-client/tfe"
 
-	log "github.com/sirupsen/logrus"
-
+	u "github.com/tsanton/terraform-provider-tfepatch/utilities"
 	m "github.com/tsanton/tfe-client/tfe/models"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/providervalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure the implementation satisfies the expected interfaces
 var (
-	_ provider.Provider = &TfeProvider{}
+	_ provider.Provider                     = &TfeProvider{}
+	_ provider.ProviderWithConfigValidators = &TfeProvider{}
 )
 
 type TfeProvider struct{}
@@ -45,43 +47,115 @@ func (p *TfeProvider) Schema(ctx context.Context, req provider.SchemaRequest, re
 		MarkdownDescription: "Terraform provider to patch/work alongside the TFE for resources/data sources that are yet to be implemented",
 		Attributes: map[string]schema.Attribute{
 			"hostname": schema.StringAttribute{
-				Required:            true,
+				Optional:            true,
 				Sensitive:           false,
-				Description:         "The Terraform Enterprise hostname to connect to. Defaults to app.terraform.io.",
-				MarkdownDescription: "The Terraform Enterprise hostname to connect to. Defaults to app.terraform.io.",
+				Description:         "The Terraform Enterprise hostname to connect to. Can be set with the TFE_HOSTNAME environment variable. Defaults to app.terraform.io.",
+				MarkdownDescription: "The Terraform Enterprise hostname to connect to. Can be set with the `TFE_HOSTNAME` environment variable. Defaults to `app.terraform.io`.",
 			},
 			"token": schema.StringAttribute{
-				Required:            true,
+				Optional:            true,
 				Sensitive:           true,
-				Description:         "The token used to authenticate with Terraform Enterprise. We recommend omitting the token which can be set as credentials in the CLI config file.",
-				MarkdownDescription: "The token used to authenticate with Terraform Enterprise. We recommend omitting the token which can be set as credentials in the CLI config file.",
+				Description:         "The token used to authenticate with Terraform Enterprise. We recommend omitting the token which can be set as credentials in the CLI config file. Falls back to the TFE_TOKEN environment variable, the TF_TOKEN_<host> environment variable, credentials.tfrc.json and the credentials blocks of the CLI config file, in that order.",
+				MarkdownDescription: "The token used to authenticate with Terraform Enterprise. We recommend omitting the token which can be set as credentials in the CLI config file. Falls back to the `TFE_TOKEN` environment variable, the `TF_TOKEN_<host>` environment variable, `credentials.tfrc.json` and the `credentials` blocks of the CLI config file, in that order.",
 			},
 			"organization": schema.StringAttribute{
-				Required:            true,
+				Optional:            true,
 				Sensitive:           false,
-				Description:         "The organization to apply to a resource if one is not defined on the resource itself.",
-				MarkdownDescription: "The organization to apply to a resource if one is not defined on the resource itself.",
+				Description:         "The organization to apply to a resource if one is not defined on the resource itself. Can be set with the TFE_ORGANIZATION environment variable.",
+				MarkdownDescription: "The organization to apply to a resource if one is not defined on the resource itself. Can be set with the `TFE_ORGANIZATION` environment variable.",
 			},
 			"ssl_skip_verify": schema.BoolAttribute{
 				Optional:            true,
 				Sensitive:           false,
-				Description:         "Whether or not to skip certificate verifications.",
-				MarkdownDescription: "Whether or not to skip certificate verifications.",
+				Description:         "Whether or not to skip certificate verifications. Can be set with the TFE_SSL_SKIP_VERIFY environment variable. Defaults to false.",
+				MarkdownDescription: "Whether or not to skip certificate verifications. Can be set with the `TFE_SSL_SKIP_VERIFY` environment variable. Defaults to `false`.",
+			},
+			"ca_cert_file": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           false,
+				Description:         "Path to a PEM encoded CA bundle trusted in addition to the system certificates, i.e. for Terraform Enterprise behind an internal CA. Conflicts with ca_cert_pem.",
+				MarkdownDescription: "Path to a PEM encoded CA bundle trusted in addition to the system certificates, i.e. for Terraform Enterprise behind an internal CA. Conflicts with `ca_cert_pem`.",
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           false,
+				Description:         "PEM encoded CA bundle trusted in addition to the system certificates. Conflicts with ca_cert_file.",
+				MarkdownDescription: "PEM encoded CA bundle trusted in addition to the system certificates. Conflicts with `ca_cert_file`.",
+			},
+			"client_cert_file": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           false,
+				Description:         "Path to a PEM encoded client certificate for mutual TLS. Requires client_key_file.",
+				MarkdownDescription: "Path to a PEM encoded client certificate for mutual TLS. Requires `client_key_file`.",
+			},
+			"client_key_file": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           false,
+				Description:         "Path to the PEM encoded private key of the client certificate. Requires client_cert_file.",
+				MarkdownDescription: "Path to the PEM encoded private key of the client certificate. Requires `client_cert_file`.",
+			},
+			"https_proxy": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           false,
+				Description:         "URL of the proxy to send API requests and uploads through. Defaults to the HTTPS_PROXY environment variable.",
+				MarkdownDescription: "URL of the proxy to send API requests and uploads through. Defaults to the `HTTPS_PROXY` environment variable.",
+			},
+			"max_retries": schema.Int64Attribute{
+				Optional:            true,
+				Sensitive:           false,
+				Description:         "The number of times a throttled (429) or failed (5xx) API request is retried. Defaults to 5.",
+				MarkdownDescription: "The number of times a throttled (429) or failed (5xx) API request is retried. Defaults to `5`.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"retry_wait_min": schema.Int64Attribute{
+				Optional:            true,
+				Sensitive:           false,
+				Description:         "The seconds to wait before the first retry, doubled for every following retry. Retry-After and X-RateLimit-Reset response headers take precedence. Defaults to 1.",
+				MarkdownDescription: "The seconds to wait before the first retry, doubled for every following retry. `Retry-After` and `X-RateLimit-Reset` response headers take precedence. Defaults to `1`.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"retry_wait_max": schema.Int64Attribute{
+				Optional:            true,
+				Sensitive:           false,
+				Description:         "The maximum seconds to wait between retries, also when the Retry-After or X-RateLimit-Reset response header asks for longer. Defaults to 30.",
+				MarkdownDescription: "The maximum seconds to wait between retries, also when the `Retry-After` or `X-RateLimit-Reset` response header asks for longer. Defaults to `30`.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
 			},
 		},
 	}
 }
 
 type providerConfig struct {
-	Hostname     types.String `tfsdk:"hostname"`
-	Token        types.String `tfsdk:"token"`
-	Organization types.String `tfsdk:"organization"`
-	VerifyTls    types.Bool   `tfsdk:"ssl_skip_verify"`
+	Hostname       types.String `tfsdk:"hostname"`
+	Token          types.String `tfsdk:"token"`
+	Organization   types.String `tfsdk:"organization"`
+	SkipTlsVerify  types.Bool   `tfsdk:"ssl_skip_verify"`
+	CaCertFile     types.String `tfsdk:"ca_cert_file"`
+	CaCertPem      types.String `tfsdk:"ca_cert_pem"`
+	ClientCertFile types.String `tfsdk:"client_cert_file"`
+	ClientKeyFile  types.String `tfsdk:"client_key_file"`
+	HttpsProxy     types.String `tfsdk:"https_proxy"`
+	MaxRetries     types.Int64  `tfsdk:"max_retries"`
+	RetryWaitMin   types.Int64  `tfsdk:"retry_wait_min"`
+	RetryWaitMax   types.Int64  `tfsdk:"retry_wait_max"`
+}
+
+// ConfigValidators returns the validations spanning multiple provider attributes.
+func (p *TfeProvider) ConfigValidators(_ context.Context) []provider.ConfigValidator {
+	return []provider.ConfigValidator{
+		providervalidator.Conflicting(path.MatchRoot("ca_cert_file"), path.MatchRoot("ca_cert_pem")),
+		providervalidator.RequiredTogether(path.MatchRoot("client_cert_file"), path.MatchRoot("client_key_file")),
+	}
 }
 
 func (p *TfeProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
-	logger := log.New()
-	logger.Info("Configuring TFE client")
+	tflog.Info(ctx, "Configuring TFE client")
 
 	// Retrieve provider data from configuration
 	var config providerConfig
@@ -91,70 +165,167 @@ func (p *TfeProvider) Configure(ctx context.Context, req provider.ConfigureReque
 		return
 	}
 
-	//TODO: verify ENV variable function
 	if config.Hostname.IsUnknown() {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("hostname"),
 			"Unknown Terraform Enterprice API Host",
 			"The provider cannot create the Terraform Enterprice API client as there is an unknown configuration value for the API host. "+
-				"Either set the value statically in the configuration, or use the provider_HOST environment variable.",
+				"Either set the value statically in the configuration, or use the TFE_HOSTNAME environment variable.",
 		)
 	}
 
-	//TODO: verify ENV variable function
 	if config.Token.IsUnknown() {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("token"),
 			"Unknown Terraform Enterprice API Token",
 			"The provider cannot create the Terraform Enterprice API client as there is an unknown configuration value for the API token. "+
-				"Either set the value statically in the configuration, or use the provider_TOKEN environment variable.",
+				"Either set the value statically in the configuration, or use the TFE_TOKEN environment variable.",
 		)
 	}
 
-	//TODO: verify ENV variable function
 	if config.Organization.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("organization"),
+			"Unknown Terraform Enterprice Organization",
+			"The provider cannot create the Terraform Enterprice API client as there is an unknown configuration value for the organization. "+
+				"Either set the value statically in the configuration, or use the TFE_ORGANIZATION environment variable.",
+		)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Fall back to the environment for attributes omitted from the configuration
+	if config.Hostname.IsNull() {
+		config.Hostname = types.StringValue(envString("TFE_HOSTNAME", defaultHostname))
+	}
+	if config.Token.IsNull() {
+		config.Token = types.StringValue(envString("TFE_TOKEN", ""))
+	}
+	if config.Organization.IsNull() {
+		config.Organization = types.StringValue(envString("TFE_ORGANIZATION", ""))
+	}
+	if config.SkipTlsVerify.IsNull() {
+		skipTlsVerify, err := envBool("TFE_SSL_SKIP_VERIFY", false)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("ssl_skip_verify"),
+				"Invalid TFE_SSL_SKIP_VERIFY environment variable",
+				"The provider cannot create the Terraform Enterprice API client as the TFE_SSL_SKIP_VERIFY environment variable must be a boolean such as true or false: "+err.Error(),
+			)
+			return
+		}
+		config.SkipTlsVerify = types.BoolValue(skipTlsVerify)
+	}
+
+	address, err := hostAddress(config.Hostname.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("hostname"),
+			"Invalid Terraform Enterprice API Host",
+			"The provider cannot create the Terraform Enterprice API client as the API host is invalid: "+err.Error(),
+		)
+		return
+	}
+
+	// Fall back to the credentials the Terraform CLI uses for the host
+	if config.Token.ValueString() == "" {
+		token, err := cliCredentialsToken(address.Host)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("token"),
+				"Unable to read Terraform CLI credentials",
+				"The provider was unable to look up the API token in the Terraform CLI configuration: "+err.Error(),
+			)
+			return
+		}
+		config.Token = types.StringValue(token)
+	}
+
+	if config.Token.ValueString() == "" {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("token"),
-			"Unknown Terraform Enterprice API Token",
-			"The provider cannot create the Terraform Enterprice API client as there is an unknown configuration value for the API token. "+
-				"Either set the value statically in the configuration, or use the provider_TOKEN environment variable.",
+			"Missing Terraform Enterprice API Token",
+			fmt.Sprintf("The provider cannot create the Terraform Enterprice API client as there is no API token for %s. "+
+				"Either set the value in the configuration, use the TFE_TOKEN or %s environment variable, or run `terraform login %s`.", address.Host, credentialsEnvName(address.Host), address.Host),
 		)
+		return
 	}
 
-	transport := &http.Transport{}
-	if transport.TLSClientConfig == nil {
-		transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	if config.MaxRetries.IsNull() {
+		config.MaxRetries = types.Int64Value(defaultMaxRetries)
+	}
+	if config.RetryWaitMin.IsNull() {
+		config.RetryWaitMin = types.Int64Value(int64(defaultRetryWaitMin / time.Second))
+	}
+	if config.RetryWaitMax.IsNull() {
+		config.RetryWaitMax = types.Int64Value(int64(defaultRetryWaitMax / time.Second))
+	}
+	if config.RetryWaitMin.ValueInt64() > config.RetryWaitMax.ValueInt64() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("retry_wait_min"),
+			"Invalid retry wait",
+			fmt.Sprintf("retry_wait_min (%d) must be less than or equal to retry_wait_max (%d).", config.RetryWaitMin.ValueInt64(), config.RetryWaitMax.ValueInt64()),
+		)
+		return
 	}
 
-	if !config.VerifyTls.ValueBool() {
+	if config.SkipTlsVerify.ValueBool() {
 		tflog.Warn(ctx, "Client configured to skip certificate verifications")
-		transport.TLSClientConfig.InsecureSkipVerify = true
 	}
-	httpClient := cleanhttp.DefaultPooledClient()
-	httpClient.Transport = transport
+	httpClient, err := newHttpClient(transportConfig{
+		skipTlsVerify:  config.SkipTlsVerify.ValueBool(),
+		caCertFile:     config.CaCertFile.ValueString(),
+		caCertPem:      config.CaCertPem.ValueString(),
+		clientCertFile: config.ClientCertFile.ValueString(),
+		clientKeyFile:  config.ClientKeyFile.ValueString(),
+		httpsProxy:     config.HttpsProxy.ValueString(),
+		token:          config.Token.ValueString(),
+		maxRetries:     int(config.MaxRetries.ValueInt64()),
+		retryWaitMin:   time.Duration(config.RetryWaitMin.ValueInt64()) * time.Second,
+		retryWaitMax:   time.Duration(config.RetryWaitMax.ValueInt64()) * time.Second,
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to configure the TFE API transport",
+			"Unable to configure the TLS and proxy settings of the TFE API client: "+err.Error(),
+		)
+		return
+	}
 
 	// Create a new TFE client config
 	cfg := m.ClientConfig{
-		Address: config.Hostname.ValueString(),
-		Token:   config.Token.ValueString(),
+		Address:    address.String(),
+		Token:      config.Token.ValueString(),
+		HttpClient: httpClient,
 	}
 
-	// Create a new TFE client.
-	client, err := tfeclient.NewClient(logger, &cfg)
+	// Create a new TFE client. It only logs while it is created, as ILogger has no context to log the later requests with
+	clientLogger := u.NewTflogLogger(ctx, clientLogSubsystem, cfg.Token)
+	defer clientLogger.Detach()
+	client, err := tfeclient.NewClient(clientLogger, &cfg)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to configure up a new TFE API Client",
 			"Unable to configure up a new TFE API Client",
 		)
+		return
+	}
+	data := &providerData{
+		client:       client,
+		httpClient:   httpClient,
+		organization: config.Organization.ValueString(),
 	}
-	resp.DataSourceData = client
-	resp.ResourceData = client
+	resp.DataSourceData = data
+	resp.ResourceData = data
 }
 
 // GetDataSources satisfies the provider.Provider interface.
 func (p *TfeProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
-		// Provider specific implementation
+		newRegistryProviderDataSource,
+		newGpgKeysDataSource,
 	}
 }
 
@@ -163,5 +334,10 @@ func (p *TfeProvider) Resources(ctx context.Context) []func() resource.Resource
 	return []func() resource.Resource{
 		newProviderRegistryResource,
 		newGpgKeyResource,
+		newRegistryProviderVersionResource,
+		newRegistryProviderPlatformResource,
+		newRegistryProviderReleaseResource,
+		newShasumsSignatureResource,
+		newSigningKeyResource,
 	}
 }
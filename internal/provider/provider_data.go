@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	api "github.com/tsanton/tfe-client/tfe"
+)
+
+// providerData is handed to resources and data sources when the provider is configured.
+type providerData struct {
+	client *api.TerraformEnterpriseClient
+	// httpClient is configured with the provider TLS and proxy settings, for uploads outside of the TFE client
+	httpClient *http.Client
+	// organization is the provider level default for resources that do not set one
+	organization string
+}
+
+// planOrganization defaults a resource's organization to the provider organization when it is omitted from the configuration.
+// It runs in ModifyPlan, after the attribute plan modifiers, and therefore requires replacement itself when the default changes.
+func planOrganization(ctx context.Context, organization string, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to default on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var configured types.String
+	diags := req.Config.GetAttribute(ctx, path.Root("organization"), &configured)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || !configured.IsNull() {
+		return
+	}
+
+	if organization == "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("organization"),
+			"Missing organization",
+			"The organization must be set on the resource or the provider, either in the configuration or with the TFE_ORGANIZATION environment variable.",
+		)
+		return
+	}
+
+	diags = resp.Plan.SetAttribute(ctx, path.Root("organization"), organization)
+	resp.Diagnostics.Append(diags...)
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var prior types.String
+	diags = req.State.GetAttribute(ctx, path.Root("organization"), &prior)
+	resp.Diagnostics.Append(diags...)
+	if prior.ValueString() != organization {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("organization"))
+	}
+}
@@ -1,11 +1,27 @@
 package provider_test
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
 	provider "github.com/tsanton/terraform-provider-tfepatch/provider"
+	m "github.com/tsanton/terraform-provider-tfepatch/provider/models"
 
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/assert"
 )
 
 var (
@@ -21,3 +37,191 @@ var (
 		},
 	}
 )
+
+func Test_provider_hostname_and_token_from_environment(t *testing.T) {
+	/* Arrange */
+	isolateCliConfig(t)
+	server := newTestApiServer(t, tokenHandler("env-token"))
+	t.Setenv("TFE_HOSTNAME", server.URL)
+	t.Setenv("TFE_TOKEN", "env-token")
+
+	/* Act & Assert */
+	assertAuthenticated(t, map[string]tftypes.Value{})
+}
+
+func Test_provider_token_from_host_environment_variable(t *testing.T) {
+	/* Arrange */
+	isolateCliConfig(t)
+	server := newTestApiServer(t, tokenHandler("host-env-token"))
+	t.Setenv("TF_TOKEN_"+strings.ReplaceAll(serverHost(t, server), ".", "_"), "host-env-token")
+
+	/* Act & Assert */
+	assertAuthenticated(t, map[string]tftypes.Value{
+		"hostname": tftypes.NewValue(tftypes.String, server.URL),
+	})
+}
+
+func Test_provider_token_from_credentials_file(t *testing.T) {
+	/* Arrange */
+	home := isolateCliConfig(t)
+	server := newTestApiServer(t, tokenHandler("login-token"))
+	credentials := fmt.Sprintf(`{"credentials": {"%s": {"token": "login-token"}}}`, serverHost(t, server))
+	assert.Nil(t, os.MkdirAll(filepath.Join(home, ".terraform.d"), 0o700))
+	assert.Nil(t, os.WriteFile(filepath.Join(home, ".terraform.d", "credentials.tfrc.json"), []byte(credentials), 0o600))
+
+	/* Act & Assert */
+	assertAuthenticated(t, map[string]tftypes.Value{
+		"hostname": tftypes.NewValue(tftypes.String, server.URL),
+	})
+}
+
+func Test_provider_token_from_cli_config_file(t *testing.T) {
+	/* Arrange */
+	home := isolateCliConfig(t)
+	server := newTestApiServer(t, tokenHandler("terraformrc-token"))
+	cliConfig := fmt.Sprintf(`
+plugin_cache_dir = "$HOME/.terraform.d/plugin-cache"
+
+credentials "%s" {
+  token = "terraformrc-token"
+}`, serverHost(t, server))
+	assert.Nil(t, os.WriteFile(filepath.Join(home, ".terraformrc"), []byte(cliConfig), 0o600))
+
+	/* Act & Assert */
+	assertAuthenticated(t, map[string]tftypes.Value{
+		"hostname": tftypes.NewValue(tftypes.String, server.URL),
+	})
+}
+
+func Test_provider_configured_token_takes_precedence(t *testing.T) {
+	/* Arrange */
+	isolateCliConfig(t)
+	server := newTestApiServer(t, tokenHandler("config-token"))
+	t.Setenv("TFE_TOKEN", "env-token")
+
+	/* Act & Assert */
+	assertAuthenticated(t, map[string]tftypes.Value{
+		"hostname": tftypes.NewValue(tftypes.String, server.URL),
+		"token":    tftypes.NewValue(tftypes.String, "config-token"),
+	})
+}
+
+func Test_provider_missing_token(t *testing.T) {
+	/* Arrange */
+	isolateCliConfig(t)
+
+	/* Act */
+	resp := configureTestProvider(provider.New()(), map[string]tftypes.Value{
+		"hostname": tftypes.NewValue(tftypes.String, "tfe.example.com"),
+	})
+
+	/* Assert */
+	assert.True(t, resp.Diagnostics.HasError())
+	assert.Contains(t, resp.Diagnostics.Errors()[0].Detail(), "TF_TOKEN_tfe_example_com")
+}
+
+func Test_provider_token_from_environment_is_not_parsed(t *testing.T) {
+	/* Arrange */
+	isolateCliConfig(t)
+	server := newTestApiServer(t, tokenHandler(`env"token\`))
+	t.Setenv("TFE_TOKEN", `env"token\`)
+
+	/* Act & Assert */
+	assertAuthenticated(t, map[string]tftypes.Value{
+		"hostname": tftypes.NewValue(tftypes.String, server.URL),
+	})
+}
+
+func Test_provider_ssl_skip_verify_from_environment(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		valid bool
+	}{
+		{name: "lower case", value: "true", valid: true},
+		{name: "upper case", value: "TRUE", valid: true},
+		{name: "digit", value: "0", valid: true},
+		{name: "yes", value: "yes"},
+		{name: "on", value: "on"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* Arrange */
+			isolateCliConfig(t)
+			t.Setenv("TFE_SSL_SKIP_VERIFY", tt.value)
+
+			/* Act */
+			resp := configureTestProvider(provider.New()(), map[string]tftypes.Value{
+				"hostname": tftypes.NewValue(tftypes.String, "tfe.example.com"),
+				"token":    tftypes.NewValue(tftypes.String, "test-token"),
+			})
+
+			/* Assert */
+			if tt.valid {
+				assert.False(t, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+				return
+			}
+			assert.Equal(t, 1, resp.Diagnostics.ErrorsCount())
+			assert.Equal(t, "Invalid TFE_SSL_SKIP_VERIFY environment variable", resp.Diagnostics.Errors()[0].Summary())
+			withPath, ok := resp.Diagnostics.Errors()[0].(interface{ Path() path.Path })
+			if assert.True(t, ok) {
+				assert.Equal(t, path.Root("ssl_skip_verify"), withPath.Path())
+			}
+		})
+	}
+}
+
+// isolateCliConfig points the home directory at an empty directory and clears the provider environment variables.
+func isolateCliConfig(t *testing.T) string {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("APPDATA", home)
+	t.Setenv("TF_CLI_CONFIG_FILE", "")
+	t.Setenv("TFE_HOSTNAME", "")
+	t.Setenv("TFE_TOKEN", "")
+	t.Setenv("TFE_ORGANIZATION", "")
+	t.Setenv("TFE_SSL_SKIP_VERIFY", "")
+	t.Setenv("TFE_SSL_SKIP_VERIFY", "")
+	return home
+}
+
+// tokenHandler answers requests authenticated with the token as if the requested object is deleted, and rejects all others.
+func tokenHandler(token string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		notFoundHandler(w, r)
+	}
+}
+
+func serverHost(t *testing.T, server *httptest.Server) string {
+	serverUrl, err := url.Parse(server.URL)
+	assert.Nil(t, err)
+	return serverUrl.Host
+}
+
+// assertAuthenticated reads a registry provider through a provider configured with the values and asserts the request was authenticated.
+func assertAuthenticated(t *testing.T, providerValues map[string]tftypes.Value) {
+	resp := readDeletedRegistryProvider(t, providerValues)
+
+	assert.False(t, resp.Diagnostics.HasError())
+	assert.True(t, resp.State.Raw.IsNull())
+}
+
+// readDeletedRegistryProvider reads a registry provider through a provider configured with the values.
+func readDeletedRegistryProvider(t *testing.T, providerValues map[string]tftypes.Value) fwresource.ReadResponse {
+	r := newTestResource(t, "tfepatch_registry_provider", providerValues)
+	state := newTestState(t, r, &m.RegistryProvider{
+		Id:           types.StringValue("test-org||demo-provider||private"),
+		Organization: types.StringValue("test-org"),
+		Namespace:    types.StringValue("test-org"),
+		Name:         types.StringValue("demo-provider"),
+		RegistryName: types.StringValue("private"),
+	})
+	resp := fwresource.ReadResponse{State: state}
+
+	r.Read(context.Background(), fwresource.ReadRequest{State: state}, &resp)
+	return resp
+}
@@ -3,22 +3,31 @@ package provider
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	m "github.com/tsanton/terraform-provider-tfepatch/provider/models"
 	api "github.com/tsanton/tfe-client/tfe"
 	apir "github.com/tsanton/tfe-client/tfe/models/request"
+	mresp "github.com/tsanton/tfe-client/tfe/models/response"
 )
 
 type GpgKeyResource struct {
-	client *api.TerraformEnterpriseClient
+	client       *api.TerraformEnterpriseClient
+	organization string
 }
 
 // Ensure the implementation satisfies the expected interfaces.
@@ -26,6 +35,7 @@ var (
 	_ resource.Resource                = &GpgKeyResource{}
 	_ resource.ResourceWithConfigure   = &GpgKeyResource{}
 	_ resource.ResourceWithImportState = &GpgKeyResource{}
+	_ resource.ResourceWithModifyPlan  = &GpgKeyResource{}
 )
 
 // newResource is a helper function to simplify the provider implementation.
@@ -38,7 +48,9 @@ func (r *GpgKeyResource) Configure(_ context.Context, req resource.ConfigureRequ
 	if req.ProviderData == nil {
 		return
 	}
-	r.client = req.ProviderData.(*api.TerraformEnterpriseClient)
+	data := req.ProviderData.(*providerData)
+	r.client = data.client
+	r.organization = data.organization
 }
 
 func (r *GpgKeyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
@@ -54,12 +66,51 @@ func (r *GpgKeyResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				Description:         "The identity of the generated key",
 				MarkdownDescription: "The identity of the generated key",
 			},
+			"fingerprint": schema.StringAttribute{
+				Computed:            true,
+				Description:         "The fingerprint of the primary key",
+				MarkdownDescription: "The fingerprint of the primary key",
+			},
+			"user_ids": schema.ListAttribute{
+				Computed:            true,
+				ElementType:         types.StringType,
+				Description:         "The user ids of the key, the primary user id first",
+				MarkdownDescription: "The user ids of the key, the primary user id first",
+			},
+			"algorithm": schema.StringAttribute{
+				Computed:            true,
+				Description:         "The public key algorithm of the primary key, i.e. RSA",
+				MarkdownDescription: "The public key algorithm of the primary key, i.e. `RSA`",
+			},
+			"bit_length": schema.Int64Attribute{
+				Computed:            true,
+				Description:         "The bit length of the primary key",
+				MarkdownDescription: "The bit length of the primary key",
+			},
+			"key_created_at": schema.StringAttribute{
+				Computed:            true,
+				Description:         "The RFC3339 timestamp of when the key was created",
+				MarkdownDescription: "The RFC3339 timestamp of when the key was created",
+			},
+			"key_expires_at": schema.StringAttribute{
+				Computed:            true,
+				Description:         "The RFC3339 timestamp of when the key expires. Null for keys that do not expire",
+				MarkdownDescription: "The RFC3339 timestamp of when the key expires. Null for keys that do not expire",
+			},
+			"retained_key_ids": schema.ListAttribute{
+				Computed:            true,
+				ElementType:         types.StringType,
+				Description:         "The key ids of previous keys that are kept registered as provider versions in the namespace are still signed with them",
+				MarkdownDescription: "The key ids of previous keys that are kept registered as provider versions in the namespace are still signed with them",
+			},
 			// Input attributes
 			"organization": schema.StringAttribute{
-				Required:            true,
-				Description:         "The organization name under which this GPG key will exist",
-				MarkdownDescription: "The organization name under which this GPG key will exist",
+				Optional:            true,
+				Computed:            true,
+				Description:         "The organization name under which this GPG key will exist. Defaults to the provider organization",
+				MarkdownDescription: "The organization name under which this GPG key will exist. Defaults to the provider organization",
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
@@ -73,12 +124,32 @@ func (r *GpgKeyResource) Schema(ctx context.Context, req resource.SchemaRequest,
 			},
 			"public_key": schema.StringAttribute{
 				Required:            true,
-				Description:         "The ASCII-armored public GPG key ",
-				MarkdownDescription: "The ASCII-armored public GPG key ",
+				Description:         "The ASCII-armored public GPG key. Must hold a single key that is neither expired nor revoked when it is registered",
+				MarkdownDescription: "The ASCII-armored public GPG key. Must hold a single key that is neither expired nor revoked when it is registered",
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					publicKeyRequiresReplace(),
+				},
+				Validators: []validator.String{
+					armoredPublicKeyValidator{},
 				},
 			},
+			"retain_previous": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				Description:         "Rotate a changed public key in place, keeping the previous key registered until no provider version in the namespace is signed with it. Defaults to false",
+				MarkdownDescription: "Rotate a changed `public_key` in place, keeping the previous key registered until no provider version in the namespace is signed with it. Defaults to `false`",
+			},
+			"adopt_existing": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				Description:         "Take a key with the same fingerprint that is already registered in the namespace into state on create rather than failing because it already exists. Defaults to false",
+				MarkdownDescription: "Take a key with the same fingerprint that is already registered in the namespace into state on create rather than failing because it already exists. Defaults to `false`",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeoutsOpts),
 		},
 	}
 }
@@ -88,7 +159,101 @@ func (r *GpgKeyResource) Metadata(_ context.Context, req resource.MetadataReques
 	resp.TypeName = req.ProviderTypeName + "_gpg_key"
 }
 
+// ModifyPlan defaults the organization to the provider organization, plans the metadata of the public key and the previous keys to retain.
+func (r *GpgKeyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	planOrganization(ctx, r.organization, req, resp)
+	if resp.Diagnostics.HasError() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan m.GpgKey
+	diags := resp.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || plan.PublicKey.IsUnknown() {
+		return
+	}
+
+	// An invalid key is reported by the validator
+	metadata, err := parsePublicKey(plan.PublicKey.ValueString())
+	if err != nil {
+		return
+	}
+	var state *m.GpgKey
+	if !req.State.Raw.IsNull() {
+		state = &m.GpgKey{}
+		diags = req.State.Get(ctx, state)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Only a key that is to be registered must be usable, so that a registered key that expired can still be rotated or destroyed
+	if err = metadata.usable(time.Now()); err != nil {
+		if state == nil || !sameGpgKey(state.PublicKey.ValueString(), plan.PublicKey.ValueString()) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("public_key"),
+				"Invalid GPG public key",
+				"The public key cannot be registered: "+err.Error(),
+			)
+			return
+		}
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("public_key"),
+			"Unusable GPG public key",
+			"The registered public key can no longer verify provider signatures, rotate it: "+err.Error(),
+		)
+	}
+	diags = gpgKeyMetadataState(ctx, &plan, metadata)
+	resp.Diagnostics.Append(diags...)
+
+	plan.RetainedKeyIds = types.ListValueMust(types.StringType, []attr.Value{})
+	// A different key without retain_previous is replaced, which deletes the previous keys
+	if state != nil && (sameGpgKey(state.PublicKey.ValueString(), plan.PublicKey.ValueString()) || plan.RetainPrevious.ValueBool()) {
+		diags = r.planRetainedKeyIds(ctx, &plan, state)
+		resp.Diagnostics.Append(diags...)
+	}
+	diags = resp.Plan.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// planRetainedKeyIds plans an in-place update: a rotated key retains the previous key id and retained keys are kept while referenced.
+func (r *GpgKeyResource) planRetainedKeyIds(ctx context.Context, plan, state *m.GpgKey) diag.Diagnostics {
+	var diags diag.Diagnostics
+	plan.Id = state.Id
+	plan.KeyId = state.KeyId
+	candidates := retainedKeyIds(state)
+	if !sameGpgKey(state.PublicKey.ValueString(), plan.PublicKey.ValueString()) {
+		plan.Id = types.StringUnknown()
+		plan.KeyId = types.StringUnknown()
+		candidates = append(candidates, state.KeyId.ValueString())
+	}
+	if len(candidates) == 0 || !plan.RetainPrevious.ValueBool() {
+		return diags
+	}
+
+	referenced, err := listReferencedKeyIds(ctx, r.client, state.Organization.ValueString(), state.Namespace.ValueString())
+	if err != nil {
+		diags.AddWarning(
+			"Unable to list the provider versions",
+			"All previous keys are retained as the key ids the provider versions of namespace "+state.Namespace.ValueString()+" are signed with could not be listed: "+err.Error(),
+		)
+	}
+	retained := []string{}
+	for _, keyId := range candidates {
+		if err != nil || referenced[strings.ToUpper(keyId)] {
+			retained = append(retained, keyId)
+		}
+	}
+	sort.Strings(retained)
+	list, d := types.ListValueFrom(ctx, types.StringType, retained)
+	diags.Append(d...)
+	plan.RetainedKeyIds = list
+	return diags
+}
+
 func (r *GpgKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = recordApiErrors(ctx)
 	var plan m.GpgKey
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -96,6 +261,14 @@ func (r *GpgKeyResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	cr, err := r.client.GpgService.Create(ctx, &apir.Gpg{
 		Data: apir.GpgData{
 			Type: "gpg-keys",
@@ -105,17 +278,23 @@ func (r *GpgKeyResource) Create(ctx context.Context, req resource.CreateRequest,
 			},
 		},
 	})
+	key := cr.Data
+	if err != nil && plan.AdoptExisting.ValueBool() && isConflict(err) {
+		key, err = r.adoptGpgKey(ctx, plan, err, &resp.Diagnostics)
+	}
 
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error reading resource",
-			"Could not read resource "+err.Error(),
-		)
+		addApiError(ctx, &resp.Diagnostics, "register the GPG key", err, "namespace", "public_key")
 		return
 	}
 
-	plan.Id = types.StringValue(fmt.Sprintf(strings.ToLower("%s||%s"), cr.Data.Attributes.Namespace, cr.Data.Attributes.KeyId))
-	plan.KeyId = types.StringValue(cr.Data.Attributes.KeyId)
+	plan.Id = types.StringValue(fmt.Sprintf(strings.ToLower("%s||%s"), key.Attributes.Namespace, key.Attributes.KeyId))
+	plan.KeyId = types.StringValue(key.Attributes.KeyId)
+	plan.RetainedKeyIds = types.ListValueMust(types.StringType, []attr.Value{})
+	if metadata, err := parsePublicKey(plan.PublicKey.ValueString()); err == nil {
+		diags = gpgKeyMetadataState(ctx, &plan, metadata)
+		resp.Diagnostics.Append(diags...)
+	}
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -123,7 +302,29 @@ func (r *GpgKeyResource) Create(ctx context.Context, req resource.CreateRequest,
 	}
 }
 
+// adoptGpgKey finds the registered key of the namespace with the fingerprint of the public key that failed to be registered because it already exists.
+// The register error is returned when there is no such key, as TFE answers invalid keys with the same status.
+func (r *GpgKeyResource) adoptGpgKey(ctx context.Context, plan m.GpgKey, registerErr error, diags *diag.Diagnostics) (mresp.GpgKeyData, error) {
+	keys, err := listGpgKeys(ctx, r.client, []string{plan.Namespace.ValueString()})
+	if err != nil {
+		return mresp.GpgKeyData{}, registerErr
+	}
+	for _, key := range keys {
+		if !sameGpgKey(plan.PublicKey.ValueString(), key.Attributes.AsciiArmor) {
+			continue
+		}
+		diags.AddWarning(
+			"Adopted existing GPG key",
+			fmt.Sprintf("The GPG key %s with the fingerprint of the public key is already registered in namespace %s and has been taken into state rather than registered. Destroying the resource deletes it.",
+				key.Attributes.KeyId, key.Attributes.Namespace),
+		)
+		return key, nil
+	}
+	return mresp.GpgKeyData{}, registerErr
+}
+
 func (r *GpgKeyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = recordApiErrors(ctx)
 	var state m.GpgKey
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -131,24 +332,58 @@ func (r *GpgKeyResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
-	rr, err := r.client.GpgService.Read(ctx, state.Namespace.ValueString(), state.KeyId.ValueString())
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
 
-	//TODO: Handle deleted resource read
+	rr, err := r.client.GpgService.Read(ctx, state.Namespace.ValueString(), state.KeyId.ValueString())
+	if isNotFound(err) {
+		// Deleted out of band: remove from state so that Terraform plans a recreate
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error reading resource",
-			"Could not read resource "+err.Error(),
-		)
+		addApiError(ctx, &resp.Diagnostics, "read the GPG key", err)
 		return
 	}
 
-	state = m.GpgKey{
-		Id:           types.StringValue(fmt.Sprintf(strings.ToLower("%s||%s"), rr.Data.Attributes.Namespace, rr.Data.Attributes.KeyId)),
-		Organization: state.Organization,
-		Namespace:    types.StringValue(rr.Data.Attributes.Namespace),
-		PublicKey:    types.StringValue(rr.Data.Attributes.AsciiArmor),
-		KeyId:        types.StringValue(rr.Data.Attributes.KeyId),
+	state.Id = types.StringValue(fmt.Sprintf(strings.ToLower("%s||%s"), rr.Data.Attributes.Namespace, rr.Data.Attributes.KeyId))
+	state.Namespace = types.StringValue(rr.Data.Attributes.Namespace)
+	// TFE re-encodes the key, keep the configured armor unless the key itself changed
+	if !sameGpgKey(state.PublicKey.ValueString(), rr.Data.Attributes.AsciiArmor) {
+		state.PublicKey = types.StringValue(rr.Data.Attributes.AsciiArmor)
+	}
+	state.KeyId = types.StringValue(rr.Data.Attributes.KeyId)
+	if metadata, err := parsePublicKey(rr.Data.Attributes.AsciiArmor); err == nil {
+		diags = gpgKeyMetadataState(ctx, &state, metadata)
+		resp.Diagnostics.Append(diags...)
+	}
+	if state.RetainPrevious.IsNull() {
+		state.RetainPrevious = types.BoolValue(false)
+	}
+	if state.AdoptExisting.IsNull() {
+		state.AdoptExisting = types.BoolValue(false)
+	}
+
+	// Retained keys deleted out of band are no longer retained
+	retained := []string{}
+	for _, keyId := range retainedKeyIds(&state) {
+		_, err := r.client.GpgService.Read(ctx, state.Namespace.ValueString(), keyId)
+		if isNotFound(err) {
+			continue
+		}
+		if err != nil {
+			addApiError(ctx, &resp.Diagnostics, "read the retained GPG key "+keyId, err)
+			return
+		}
+		retained = append(retained, keyId)
 	}
+	state.RetainedKeyIds, diags = types.ListValueFrom(ctx, types.StringType, retained)
+	resp.Diagnostics.Append(diags...)
 
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -157,11 +392,83 @@ func (r *GpgKeyResource) Read(ctx context.Context, req resource.ReadRequest, res
 	}
 }
 
-// No update as all attributes require replacement if changed
+// Update persists a re-encoded public_key, rotates the key when retain_previous is set and deletes the previous keys that are no longer retained
 func (r *GpgKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = recordApiErrors(ctx)
+	var plan, state m.GpgKey
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	plan.Id = state.Id
+	plan.KeyId = state.KeyId
+	candidates := retainedKeyIds(&state)
+	if !sameGpgKey(state.PublicKey.ValueString(), plan.PublicKey.ValueString()) {
+		cr, err := r.client.GpgService.Create(ctx, &apir.Gpg{
+			Data: apir.GpgData{
+				Type: "gpg-keys",
+				Attributes: apir.GpgDataAttributes{
+					AsciiArmor: plan.PublicKey.ValueString(),
+					Namespace:  plan.Namespace.ValueString(),
+				},
+			},
+		})
+		if err != nil {
+			addApiError(ctx, &resp.Diagnostics, "register the rotated GPG key", err, "namespace", "public_key")
+			return
+		}
+		plan.Id = types.StringValue(fmt.Sprintf(strings.ToLower("%s||%s"), cr.Data.Attributes.Namespace, cr.Data.Attributes.KeyId))
+		plan.KeyId = types.StringValue(cr.Data.Attributes.KeyId)
+		candidates = append(candidates, state.KeyId.ValueString())
+	}
+	if plan.Fingerprint.IsUnknown() {
+		if metadata, err := parsePublicKey(plan.PublicKey.ValueString()); err == nil {
+			diags = gpgKeyMetadataState(ctx, &plan, metadata)
+			resp.Diagnostics.Append(diags...)
+		}
+	}
+
+	planned := map[string]bool{}
+	if !plan.RetainedKeyIds.IsUnknown() {
+		for _, keyId := range retainedKeyIds(&plan) {
+			planned[keyId] = true
+		}
+	}
+	retained := []string{}
+	for _, keyId := range candidates {
+		if plan.RetainedKeyIds.IsUnknown() || planned[keyId] {
+			retained = append(retained, keyId)
+			continue
+		}
+		err := r.client.GpgService.Delete(ctx, state.Namespace.ValueString(), keyId)
+		if err != nil && !isNotFound(err) {
+			addApiError(ctx, &resp.Diagnostics, "delete the previous GPG key "+keyId, err)
+			retained = append(retained, keyId)
+		}
+	}
+	sort.Strings(retained)
+	plan.RetainedKeyIds, diags = types.ListValueFrom(ctx, types.StringType, retained)
+	resp.Diagnostics.Append(diags...)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
 }
 
+// Delete deletes the key and the retained previous keys. With retain_previous, keys that provider versions are still signed with are left registered
 func (r *GpgKeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = recordApiErrors(ctx)
 	var state m.GpgKey
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -169,24 +476,95 @@ func (r *GpgKeyResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
-	err := r.client.GpgService.Delete(ctx, state.Namespace.ValueString(), state.KeyId.ValueString())
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
 
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error reading resource",
-			"Could not read resource "+err.Error(),
+	referenced := map[string]bool{}
+	if state.RetainPrevious.ValueBool() {
+		var err error
+		referenced, err = listReferencedKeyIds(ctx, r.client, state.Organization.ValueString(), state.Namespace.ValueString())
+		if err != nil {
+			addApiError(ctx, &resp.Diagnostics, "list the key ids the provider versions of namespace "+state.Namespace.ValueString()+" are signed with", err)
+			return
+		}
+	}
+
+	left := []string{}
+	for _, keyId := range append([]string{state.KeyId.ValueString()}, retainedKeyIds(&state)...) {
+		if referenced[strings.ToUpper(keyId)] {
+			left = append(left, keyId)
+			continue
+		}
+		// A key already deleted, i.e. by a replacement created before this one was destroyed, is gone as intended
+		err := r.client.GpgService.Delete(ctx, state.Namespace.ValueString(), keyId)
+		if err != nil && !isNotFound(err) {
+			addApiError(ctx, &resp.Diagnostics, "delete the GPG key "+keyId, err)
+			return
+		}
+	}
+	if len(left) > 0 {
+		resp.Diagnostics.AddWarning(
+			"GPG keys left registered",
+			"Keys "+strings.Join(left, ", ")+" are left registered as provider versions in namespace "+state.Namespace.ValueString()+" are signed with them. Delete them once no version references them.",
 		)
-		return
 	}
 
 	resp.State.RemoveResource(ctx)
 }
 
-// TODO: test import
+// ImportState imports by '<organization>/<namespace>/<key_id>'.
+// The former '<namespace>||<key_id>' id is still accepted and resolves the organization from the provider, else the namespace.
 func (r *GpgKeyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import ID and save to the attributes that are utilized by the Read-function
-	parts := strings.Split(req.ID, "||")
-	resp.State.SetAttribute(ctx, path.Root("namespace"), parts[0])
-	resp.State.SetAttribute(ctx, path.Root("key_id"), parts[1])
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	var organization, namespace, keyId string
+	if parts, ok := splitImportId(req.ID, "/", 3); ok {
+		organization, namespace, keyId = parts[0], parts[1], parts[2]
+	} else if parts, ok := splitImportId(req.ID, "||", 2); ok {
+		namespace, keyId = parts[0], parts[1]
+		organization = r.organization
+		if organization == "" {
+			organization = namespace
+		}
+	} else {
+		resp.Diagnostics.AddError(
+			"Invalid import id",
+			fmt.Sprintf("Expected import id on the format '<organization>/<namespace>/<key_id>', got: %s", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("organization"), organization)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("namespace"), namespace)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("key_id"), keyId)...)
+}
+
+// gpgKeyMetadataState sets the computed key metadata attributes.
+func gpgKeyMetadataState(ctx context.Context, state *m.GpgKey, metadata gpgKeyMetadata) diag.Diagnostics {
+	userIds, diags := types.ListValueFrom(ctx, types.StringType, metadata.userIds)
+	state.Fingerprint = types.StringValue(metadata.fingerprint)
+	state.UserIds = userIds
+	state.Algorithm = types.StringValue(metadata.algorithm)
+	state.BitLength = types.Int64Value(metadata.bitLength)
+	state.KeyCreatedAt = types.StringValue(metadata.createdAt.Format(time.RFC3339))
+	state.KeyExpiresAt = types.StringNull()
+	if metadata.expiresAt != nil {
+		state.KeyExpiresAt = types.StringValue(metadata.expiresAt.Format(time.RFC3339))
+	}
+	return diags
+}
+
+// retainedKeyIds returns the retained key ids, none when unknown or null.
+func retainedKeyIds(state *m.GpgKey) []string {
+	keyIds := []string{}
+	for _, keyId := range state.RetainedKeyIds.Elements() {
+		if value, ok := keyId.(types.String); ok && !value.IsNull() && !value.IsUnknown() {
+			keyIds = append(keyIds, value.ValueString())
+		}
+	}
+	return keyIds
 }
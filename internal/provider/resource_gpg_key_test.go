@@ -3,28 +3,36 @@ package provider_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/stretchr/testify/assert"
+	m "github.com/tsanton/terraform-provider-tfepatch/provider/models"
 	u "github.com/tsanton/terraform-provider-tfepatch/utilities"
-	"golang.org/x/crypto/openpgp"
-	"golang.org/x/crypto/openpgp/armor"
-	"golang.org/x/crypto/openpgp/packet"
 )
 
 func Test_provider_gpg_key(t *testing.T) {
+	useCassette(t)
 	/* Arrange */
 	t.Log("Arranging")
 	orgName := u.GetEnv("TFE_ORG_NAME", "")
 	namespace := orgName
-	entity, err := openpgp.NewEntity("Gruntwork", "Integration test GPG key", "donotreply@gruntwork.com", &packet.Config{RSABits: 4096})
-	if err != nil {
-		t.Log("Unable to generate GPG key entity")
-		t.FailNow()
-	}
+	entity := loadCassetteGpgEntity(t)
 	publicKey, err := generateGpgKey(entity)
 	if err != nil {
 		t.Log("Unable to generate GPG key")
@@ -54,8 +62,24 @@ EOF
 					resource.TestCheckResourceAttr("tfepatch_gpg_key.this", "organization", orgName),
 					resource.TestCheckResourceAttr("tfepatch_gpg_key.this", "namespace", namespace),
 					resource.TestCheckResourceAttr("tfepatch_gpg_key.this", "public_key", publicKey),
+					resource.TestCheckResourceAttr("tfepatch_gpg_key.this", "fingerprint", fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint)),
+					resource.TestCheckResourceAttr("tfepatch_gpg_key.this", "algorithm", "RSA"),
+					resource.TestCheckResourceAttr("tfepatch_gpg_key.this", "bit_length", "4096"),
 				),
 			},
+			//--------------------------------------------------------------------------
+			//--- Import testing
+			//--------------------------------------------------------------------------
+			{
+				ResourceName: "tfepatch_gpg_key.this",
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					return fmt.Sprintf("%s/%s/%s", orgName, namespace, s.RootModule().Resources["tfepatch_gpg_key.this"].Primary.Attributes["key_id"]), nil
+				},
+				ImportStateVerify: true,
+				// TFE re-encodes the armor of the key
+				ImportStateVerifyIgnore: []string{"public_key"},
+			},
 			{
 				RefreshState: true,
 				// Destroy: true,
@@ -70,6 +94,9 @@ EOF
 }
 
 func Test_provider_gpg_key_with_gpg_provider(t *testing.T) {
+	if cassettes != nil {
+		t.Skip("the gpg provider generates a new key on every run, which cannot be replayed")
+	}
 	/* Arrange */
 	t.Log("Arranging")
 	orgName := u.GetEnv("TFE_ORG_NAME", "")
@@ -104,7 +131,7 @@ func Test_provider_gpg_key_with_gpg_provider(t *testing.T) {
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("tfepatch_gpg_key.this", "organization", orgName),
 					resource.TestCheckResourceAttr("tfepatch_gpg_key.this", "namespace", namespace),
-					// resource.TestCheckResourceAttrPair("tfepatch_gpg_key.this", "public_key", "gpg_private_key.this", "public_key"),
+					resource.TestCheckResourceAttrPair("tfepatch_gpg_key.this", "public_key", "gpg_private_key.this", "public_key"),
 				),
 			},
 			{
@@ -120,6 +147,29 @@ func Test_provider_gpg_key_with_gpg_provider(t *testing.T) {
 	})
 }
 
+func Test_gpg_key_deleted_out_of_band(t *testing.T) {
+	/* Arrange */
+	server := newTestApiServer(t, notFoundHandler)
+	r := newTestResource(t, "tfepatch_gpg_key", testProviderValues(server.URL))
+	state := newTestState(t, r, &m.GpgKey{
+		Id:             types.StringValue("test-org||32966F3FB5AC1129"),
+		Organization:   types.StringValue("test-org"),
+		Namespace:      types.StringValue("test-org"),
+		PublicKey:      types.StringValue("-----BEGIN PGP PUBLIC KEY BLOCK-----"),
+		KeyId:          types.StringValue("32966F3FB5AC1129"),
+		UserIds:        types.ListNull(types.StringType),
+		RetainedKeyIds: types.ListNull(types.StringType),
+	})
+	resp := fwresource.ReadResponse{State: state}
+
+	/* Act */
+	r.Read(context.Background(), fwresource.ReadRequest{State: state}, &resp)
+
+	/* Assert */
+	assert.False(t, resp.Diagnostics.HasError())
+	assert.True(t, resp.State.Raw.IsNull())
+}
+
 func generateGpgKey(entity *openpgp.Entity) (string, error) {
 	var publicKeyBuf bytes.Buffer
 	err := entity.Serialize(&publicKeyBuf)
@@ -144,3 +194,386 @@ func generateGpgKey(entity *openpgp.Entity) (string, error) {
 
 	return publicKeyArmorBuf.String(), nil
 }
+
+func Test_gpg_key_read_keeps_configured_armor(t *testing.T) {
+	/* Arrange */
+	entity := newTestGpgEntity(t, time.Now(), 0)
+	configured := armoredGpgKeys(t, openpgp.PublicKeyType, entity)
+	server := newTestApiServer(t, gpgKeyHandler(reencodedGpgKey(configured)))
+	r := newTestResource(t, "tfepatch_gpg_key", testProviderValues(server.URL))
+	state := newTestState(t, r, &m.GpgKey{
+		Id:             types.StringValue("test-org||32966f3fb5ac1129"),
+		Organization:   types.StringValue("test-org"),
+		Namespace:      types.StringValue("test-org"),
+		PublicKey:      types.StringValue(configured),
+		KeyId:          types.StringValue("32966F3FB5AC1129"),
+		UserIds:        types.ListNull(types.StringType),
+		RetainedKeyIds: types.ListNull(types.StringType),
+	})
+	resp := fwresource.ReadResponse{State: state}
+
+	/* Act */
+	r.Read(context.Background(), fwresource.ReadRequest{State: state}, &resp)
+
+	/* Assert */
+	assert.False(t, resp.Diagnostics.HasError())
+	var read m.GpgKey
+	resp.State.Get(context.Background(), &read)
+	assert.Equal(t, configured, read.PublicKey.ValueString())
+	assert.Equal(t, fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint), read.Fingerprint.ValueString())
+}
+
+func Test_gpg_key_read_detects_replaced_key(t *testing.T) {
+	/* Arrange */
+	configured := armoredGpgKeys(t, openpgp.PublicKeyType, newTestGpgEntity(t, time.Now(), 0))
+	replaced := armoredGpgKeys(t, openpgp.PublicKeyType, newTestGpgEntity(t, time.Now(), 0))
+	server := newTestApiServer(t, gpgKeyHandler(replaced))
+	r := newTestResource(t, "tfepatch_gpg_key", testProviderValues(server.URL))
+	state := newTestState(t, r, &m.GpgKey{
+		Id:             types.StringValue("test-org||32966f3fb5ac1129"),
+		Organization:   types.StringValue("test-org"),
+		Namespace:      types.StringValue("test-org"),
+		PublicKey:      types.StringValue(configured),
+		KeyId:          types.StringValue("32966F3FB5AC1129"),
+		UserIds:        types.ListNull(types.StringType),
+		RetainedKeyIds: types.ListNull(types.StringType),
+	})
+	resp := fwresource.ReadResponse{State: state}
+
+	/* Act */
+	r.Read(context.Background(), fwresource.ReadRequest{State: state}, &resp)
+
+	/* Assert */
+	assert.False(t, resp.Diagnostics.HasError())
+	var read m.GpgKey
+	resp.State.Get(context.Background(), &read)
+	assert.Equal(t, replaced, read.PublicKey.ValueString())
+}
+
+func Test_gpg_key_plan_rotation_retains_referenced_keys(t *testing.T) {
+	/* Arrange */
+	server, _ := newGpgRotationApiServer(t, "OLDKEY", "USEDKEY")
+	r := newTestResource(t, "tfepatch_gpg_key", testProviderValues(server.URL))
+	prior := armoredGpgKeys(t, openpgp.PublicKeyType, newTestGpgEntity(t, time.Now(), 0))
+	rotated := armoredGpgKeys(t, openpgp.PublicKeyType, newTestGpgEntity(t, time.Now(), 0))
+	req := newTestModifyPlanRequest(t, r, map[string]tftypes.Value{
+		"namespace":       tftypes.NewValue(tftypes.String, "test-org"),
+		"public_key":      tftypes.NewValue(tftypes.String, rotated),
+		"retain_previous": tftypes.NewValue(tftypes.Bool, true),
+	}, &m.GpgKey{
+		Id:             types.StringValue("test-org||oldkey"),
+		Organization:   types.StringValue("test-org"),
+		Namespace:      types.StringValue("test-org"),
+		PublicKey:      types.StringValue(prior),
+		KeyId:          types.StringValue("OLDKEY"),
+		UserIds:        types.ListNull(types.StringType),
+		RetainPrevious: types.BoolValue(true),
+		RetainedKeyIds: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("USEDKEY"), types.StringValue("UNUSEDKEY")}),
+	})
+	resp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+
+	/* Act */
+	r.(fwresource.ResourceWithModifyPlan).ModifyPlan(context.Background(), req, &resp)
+
+	/* Assert */
+	assert.False(t, resp.Diagnostics.HasError())
+	assert.Empty(t, resp.RequiresReplace)
+	var plan m.GpgKey
+	resp.Plan.Get(context.Background(), &plan)
+	assert.True(t, plan.KeyId.IsUnknown())
+	assert.Equal(t, types.ListValueMust(types.StringType, []attr.Value{types.StringValue("OLDKEY"), types.StringValue("USEDKEY")}), plan.RetainedKeyIds)
+}
+
+func Test_gpg_key_update_rotates_key(t *testing.T) {
+	/* Arrange */
+	server, deleted := newGpgRotationApiServer(t, "OLDKEY")
+	r := newTestResource(t, "tfepatch_gpg_key", testProviderValues(server.URL))
+	prior := armoredGpgKeys(t, openpgp.PublicKeyType, newTestGpgEntity(t, time.Now(), 0))
+	rotated := armoredGpgKeys(t, openpgp.PublicKeyType, newTestGpgEntity(t, time.Now(), 0))
+	state := newTestState(t, r, &m.GpgKey{
+		Id:             types.StringValue("test-org||oldkey"),
+		Organization:   types.StringValue("test-org"),
+		Namespace:      types.StringValue("test-org"),
+		PublicKey:      types.StringValue(prior),
+		KeyId:          types.StringValue("OLDKEY"),
+		UserIds:        types.ListNull(types.StringType),
+		RetainPrevious: types.BoolValue(true),
+		RetainedKeyIds: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("UNUSEDKEY")}),
+	})
+	plan := newTestState(t, r, &m.GpgKey{
+		Id:             types.StringUnknown(),
+		Organization:   types.StringValue("test-org"),
+		Namespace:      types.StringValue("test-org"),
+		PublicKey:      types.StringValue(rotated),
+		KeyId:          types.StringUnknown(),
+		Fingerprint:    types.StringUnknown(),
+		UserIds:        types.ListUnknown(types.StringType),
+		RetainPrevious: types.BoolValue(true),
+		RetainedKeyIds: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("OLDKEY")}),
+	})
+	resp := fwresource.UpdateResponse{State: state}
+
+	/* Act */
+	r.Update(context.Background(), fwresource.UpdateRequest{Plan: tfsdk.Plan(plan), State: state}, &resp)
+
+	/* Assert */
+	assert.False(t, resp.Diagnostics.HasError())
+	var updated m.GpgKey
+	resp.State.Get(context.Background(), &updated)
+	assert.Equal(t, "NEWKEY", updated.KeyId.ValueString())
+	assert.Equal(t, "test-org||NEWKEY", updated.Id.ValueString())
+	assert.False(t, updated.Fingerprint.IsUnknown())
+	assert.Equal(t, types.ListValueMust(types.StringType, []attr.Value{types.StringValue("OLDKEY")}), updated.RetainedKeyIds)
+	assert.Equal(t, []string{"UNUSEDKEY"}, *deleted)
+}
+
+func Test_gpg_key_delete_leaves_referenced_keys(t *testing.T) {
+	/* Arrange */
+	server, deleted := newGpgRotationApiServer(t, "OLDKEY")
+	r := newTestResource(t, "tfepatch_gpg_key", testProviderValues(server.URL))
+	state := newTestState(t, r, &m.GpgKey{
+		Id:             types.StringValue("test-org||newkey"),
+		Organization:   types.StringValue("test-org"),
+		Namespace:      types.StringValue("test-org"),
+		PublicKey:      types.StringValue("-----BEGIN PGP PUBLIC KEY BLOCK-----"),
+		KeyId:          types.StringValue("NEWKEY"),
+		UserIds:        types.ListNull(types.StringType),
+		RetainPrevious: types.BoolValue(true),
+		RetainedKeyIds: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("OLDKEY")}),
+	})
+	resp := fwresource.DeleteResponse{State: state}
+
+	/* Act */
+	r.Delete(context.Background(), fwresource.DeleteRequest{State: state}, &resp)
+
+	/* Assert */
+	assert.False(t, resp.Diagnostics.HasError())
+	assert.Len(t, resp.Diagnostics.Warnings(), 1)
+	assert.Contains(t, resp.Diagnostics.Warnings()[0].Detail(), "OLDKEY")
+	assert.True(t, resp.State.Raw.IsNull())
+	assert.Equal(t, []string{"NEWKEY"}, *deleted)
+}
+
+func Test_gpg_key_import_id(t *testing.T) {
+	tests := []struct {
+		name                           string
+		id                             string
+		organization, namespace, keyId string
+	}{
+		{name: "organization qualified", id: "test-org/other-org/32966F3FB5AC1129", organization: "test-org", namespace: "other-org", keyId: "32966F3FB5AC1129"},
+		{name: "legacy", id: "other-org||32966F3FB5AC1129", organization: "test-org", namespace: "other-org", keyId: "32966F3FB5AC1129"},
+		{name: "too many parts", id: "test-org/other-org/32966F3FB5AC1129/extra"},
+		{name: "empty key id", id: "other-org||"},
+		{name: "no separator", id: "32966F3FB5AC1129"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* Arrange */
+			server := newTestApiServer(t, notFoundHandler)
+			r := newTestResource(t, "tfepatch_gpg_key", testProviderValues(server.URL))
+
+			/* Act */
+			resp := importTestState(r, tt.id)
+
+			/* Assert */
+			if tt.keyId == "" {
+				assert.True(t, resp.Diagnostics.HasError())
+				assert.Equal(t, "Invalid import id", resp.Diagnostics.Errors()[0].Summary())
+				return
+			}
+			assert.False(t, resp.Diagnostics.HasError())
+			var state m.GpgKey
+			resp.State.Get(context.Background(), &state)
+			assert.Equal(t, tt.organization, state.Organization.ValueString())
+			assert.Equal(t, tt.namespace, state.Namespace.ValueString())
+			assert.Equal(t, tt.keyId, state.KeyId.ValueString())
+		})
+	}
+}
+
+// newGpgRotationApiServer serves the private provider test-org/demo-provider with a version signed by each referenced key id,
+// registers every GPG key as NEWKEY and records the key ids that are deleted.
+func newGpgRotationApiServer(t *testing.T, referenced ...string) (*httptest.Server, *[]string) {
+	deleted := []string{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/organizations/test-org/registry-providers", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		_, _ = w.Write([]byte(`{"data": [{"id": "prov-123", "type": "registry-providers", "attributes": {
+			"name": "demo-provider", "namespace": "test-org", "registry-name": "private"}}],
+			"meta": {"pagination": {"current-page": 1, "next-page": null, "total-pages": 1}}}`))
+	})
+	mux.HandleFunc("/api/v2/organizations/test-org/registry-providers/private/test-org/demo-provider/versions", func(w http.ResponseWriter, r *http.Request) {
+		versions := []interface{}{}
+		for i, keyId := range referenced {
+			versions = append(versions, map[string]interface{}{
+				"id":         fmt.Sprintf("provver-%d", i),
+				"attributes": map[string]interface{}{"version": fmt.Sprintf("1.%d.0", i), "key-id": keyId},
+			})
+		}
+		body, _ := json.Marshal(map[string]interface{}{
+			"data": versions,
+			"meta": map[string]interface{}{"pagination": map[string]interface{}{"current-page": 1, "next-page": nil, "total-pages": 1}},
+		})
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		_, _ = w.Write(body)
+	})
+	mux.HandleFunc("/api/registry/private/v2/gpg-keys", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"data": {"id": "2", "type": "gpg-keys", "attributes": {"key-id": "NEWKEY", "namespace": "test-org"}}}`))
+	})
+	mux.HandleFunc("/api/registry/private/v2/gpg-keys/test-org/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			notFoundHandler(w, r)
+			return
+		}
+		deleted = append(deleted, strings.TrimPrefix(r.URL.Path, "/api/registry/private/v2/gpg-keys/test-org/"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return newTestApiServer(t, mux.ServeHTTP), &deleted
+}
+
+// gpgKeyHandler answers every request with the GPG key 32966F3FB5AC1129 of namespace test-org holding the armored key.
+func gpgKeyHandler(armored string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, _ := json.Marshal(map[string]interface{}{
+			"data": map[string]interface{}{
+				"type": "gpg-keys",
+				"id":   "1",
+				"attributes": map[string]interface{}{
+					"ascii-armor": armored,
+					"key-id":      "32966F3FB5AC1129",
+					"namespace":   "test-org",
+					"source":      "TerraformCloud",
+					"created-at":  "2023-01-02T03:04:05Z",
+					"updated-at":  "2023-01-02T03:04:05Z",
+				},
+			},
+		})
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		_, _ = w.Write(body)
+	}
+}
+
+func Test_gpg_key_lifecycle(t *testing.T) {
+	/* Arrange */
+	requireTerraformCli(t)
+	fake := newFakeTfeApi(t)
+	first := newTestGpgEntity(t, time.Now(), 0)
+	second := newTestGpgEntity(t, time.Now(), 0)
+	config := func(entity *openpgp.Entity) string {
+		return fake.providerConfig() + fmt.Sprintf(`
+				resource "tfepatch_gpg_key" "this" {
+					namespace       = "test-org"
+					retain_previous = true
+					public_key      = trimspace(<<EOF
+%s
+EOF
+)
+				  }
+				`, armoredGpgKeys(t, openpgp.PublicKeyType, entity))
+	}
+	keyId := func(entity *openpgp.Entity) string {
+		return fmt.Sprintf("%016X", entity.PrimaryKey.KeyId)
+	}
+
+	/* Act */
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(_ *terraform.State) error {
+			if fake.gpgKey(fakeOrganization, keyId(second)) != nil {
+				return fmt.Errorf("GPG key %s was not deleted", keyId(second))
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config(first),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("tfepatch_gpg_key.this", "organization", fakeOrganization),
+					resource.TestCheckResourceAttr("tfepatch_gpg_key.this", "key_id", keyId(first)),
+					resource.TestCheckResourceAttr("tfepatch_gpg_key.this", "retained_key_ids.#", "0"),
+				),
+			},
+			{
+				ResourceName:            "tfepatch_gpg_key.this",
+				ImportState:             true,
+				ImportStateId:           "test-org/test-org/" + keyId(first),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"retain_previous"},
+			},
+			//--------------------------------------------------------------------------
+			//--- Rotation without provider versions signed by the previous key
+			//--------------------------------------------------------------------------
+			{
+				Config: config(second),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("tfepatch_gpg_key.this", "key_id", keyId(second)),
+					resource.TestCheckResourceAttr("tfepatch_gpg_key.this", "retained_key_ids.#", "0"),
+					func(_ *terraform.State) error {
+						if fake.gpgKey(fakeOrganization, keyId(first)) != nil {
+							return fmt.Errorf("the unreferenced previous key %s was not deleted", keyId(first))
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func Test_gpg_key_adopt_existing(t *testing.T) {
+	tests := []struct {
+		name      string
+		adopt     bool
+		namespace string
+		summary   string
+	}{
+		{name: "adopts the key with the same fingerprint", adopt: true, namespace: fakeOrganization},
+		{name: "fails without adopt_existing", adopt: false, namespace: fakeOrganization, summary: "Invalid TFE request"},
+		// The key is not registered in the namespace, so the create error is reported
+		{name: "fails when the key is not registered", adopt: true, namespace: "other-org", summary: "Invalid TFE request"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* Arrange */
+			fake := newFakeTfeApi(t)
+			providerValues := testProviderValues(fake.server.URL)
+			providerValues["token"] = tftypes.NewValue(tftypes.String, fakeToken)
+			r := newTestResource(t, "tfepatch_gpg_key", providerValues)
+			entity := newTestGpgEntity(t, time.Now(), 0)
+			create := func(namespace string, adopt bool) fwresource.CreateResponse {
+				req := newTestModifyPlanRequest(t, r, map[string]tftypes.Value{
+					"namespace":      tftypes.NewValue(tftypes.String, namespace),
+					"public_key":     tftypes.NewValue(tftypes.String, armoredGpgKeys(t, openpgp.PublicKeyType, entity)),
+					"adopt_existing": tftypes.NewValue(tftypes.Bool, adopt),
+				}, nil)
+				planResp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+				r.(fwresource.ResourceWithModifyPlan).ModifyPlan(context.Background(), req, &planResp)
+				resp := fwresource.CreateResponse{State: tfsdk.State{Schema: req.State.Schema, Raw: req.State.Raw}}
+				r.Create(context.Background(), fwresource.CreateRequest{Config: req.Config, Plan: planResp.Plan}, &resp)
+				return resp
+			}
+			existing := create(fakeOrganization, false)
+			assert.False(t, existing.Diagnostics.HasError(), "%v", existing.Diagnostics)
+
+			/* Act */
+			resp := create(tt.namespace, tt.adopt)
+
+			/* Assert */
+			if tt.summary != "" {
+				assert.Equal(t, 1, resp.Diagnostics.ErrorsCount())
+				assert.Equal(t, tt.summary, resp.Diagnostics.Errors()[0].Summary())
+				return
+			}
+			assert.False(t, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+			assert.Equal(t, 1, resp.Diagnostics.WarningsCount())
+			assert.Equal(t, "Adopted existing GPG key", resp.Diagnostics.Warnings()[0].Summary())
+			var state m.GpgKey
+			assert.False(t, resp.State.Get(context.Background(), &state).HasError())
+			assert.Equal(t, fmt.Sprintf("%016X", entity.PrimaryKey.KeyId), state.KeyId.ValueString())
+			assert.Equal(t, fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint), state.Fingerprint.ValueString())
+			assert.Len(t, fake.gpgKeys, 1)
+		})
+	}
+}
@@ -3,12 +3,17 @@ package provider
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
@@ -17,10 +22,18 @@ import (
 	api "github.com/tsanton/tfe-client/tfe"
 	"github.com/tsanton/tfe-client/tfe/models/enum"
 	apir "github.com/tsanton/tfe-client/tfe/models/request"
+	mresp "github.com/tsanton/tfe-client/tfe/models/response"
+)
+
+// The naming rules of provider addresses in the Terraform registry
+var (
+	publicNamespacePattern = regexp.MustCompile(`^[a-zA-Z0-9]+(-[a-zA-Z0-9]+)*$`)
+	publicNamePattern      = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
 )
 
 type ProviderRegistryResource struct {
-	client *api.TerraformEnterpriseClient
+	client       *api.TerraformEnterpriseClient
+	organization string
 }
 
 // Ensure the implementation satisfies the expected interfaces.
@@ -28,6 +41,7 @@ var (
 	_ resource.Resource                = &ProviderRegistryResource{}
 	_ resource.ResourceWithConfigure   = &ProviderRegistryResource{}
 	_ resource.ResourceWithImportState = &ProviderRegistryResource{}
+	_ resource.ResourceWithModifyPlan  = &ProviderRegistryResource{}
 )
 
 // newResource is a helper function to simplify the provider implementation.
@@ -40,7 +54,9 @@ func (r *ProviderRegistryResource) Configure(_ context.Context, req resource.Con
 	if req.ProviderData == nil {
 		return
 	}
-	r.client = req.ProviderData.(*api.TerraformEnterpriseClient)
+	data := req.ProviderData.(*providerData)
+	r.client = data.client
+	r.organization = data.organization
 }
 
 func (r *ProviderRegistryResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
@@ -48,30 +64,32 @@ func (r *ProviderRegistryResource) Schema(ctx context.Context, req resource.Sche
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Computed:            true,
-				Description:         "Unique id for this resource",
-				MarkdownDescription: "Unique id for this resource",
+				Description:         "Unique id for this resource, on the format <namespace>||<name>||<registry_name>. Note that it differs from the organization qualified import id",
+				MarkdownDescription: "Unique id for this resource, on the format `<namespace>||<name>||<registry_name>`. Note that it differs from the organization qualified import id",
 			},
 			// Input attributes
 			"organization": schema.StringAttribute{
-				Required:            true,
-				Description:         "The organization name under which this provider registry will exist",
-				MarkdownDescription: "The organization name under which this provider registry will exist",
+				Optional:            true,
+				Computed:            true,
+				Description:         "The organization name under which this provider registry will exist. Defaults to the provider organization",
+				MarkdownDescription: "The organization name under which this provider registry will exist. Defaults to the provider organization",
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
 			"namespace": schema.StringAttribute{
 				Required:            true,
-				Description:         "The namespace under which the provider will exist. For provider registries with 'registy_name' == 'private' the namespace must match organization name", //TODO: test that namespace equals organization name when registry_name is private
-				MarkdownDescription: "The namespace under which the provider will exist. For provider registries with 'registy_name' == 'private' the namespace must match organization name", //TODO: test that namespace equals organization name when registry_name is private
+				Description:         "The namespace under which the provider will exist. For provider registries with 'registry_name' == 'private' the namespace must match the organization name, ignoring case",
+				MarkdownDescription: "The namespace under which the provider will exist. For provider registries with 'registry_name' == 'private' the namespace must match the organization name, ignoring case",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
 			"name": schema.StringAttribute{
 				Required:            true,
-				Description:         "The name of the provider",
-				MarkdownDescription: "The name of the provider",
+				Description:         "The name of the provider. Public provider names must be lower case letters, digits and single dashes",
+				MarkdownDescription: "The name of the provider. Public provider names must be lower case letters, digits and single dashes",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -87,6 +105,29 @@ func (r *ProviderRegistryResource) Schema(ctx context.Context, req resource.Sche
 					stringvalidator.OneOf("private", "public"),
 				},
 			},
+			"deletion_protection": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Description:         "Fail to delete or replace the provider while it has published versions, as deleting it deletes every version. Defaults to true for private providers and false for public providers",
+				MarkdownDescription: "Fail to delete or replace the provider while it has published versions, as deleting it deletes every version. Defaults to `true` for private providers and `false` for public providers",
+			},
+			"force_destroy": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				Description:         "Delete the provider with its published versions despite deletion_protection. Must be applied before the provider is deleted or replaced. Defaults to false",
+				MarkdownDescription: "Delete the provider with its published versions despite `deletion_protection`. Must be applied before the provider is deleted or replaced. Defaults to `false`",
+			},
+			"adopt_existing": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				Description:         "Take an existing provider with the same address into state on create rather than failing because it already exists. Defaults to false",
+				MarkdownDescription: "Take an existing provider with the same address into state on create rather than failing because it already exists. Defaults to `false`",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeoutsOpts),
 		},
 	}
 }
@@ -96,7 +137,84 @@ func (r *ProviderRegistryResource) Metadata(_ context.Context, req resource.Meta
 	resp.TypeName = req.ProviderTypeName + "_registry_provider"
 }
 
+// ModifyPlan defaults the organization to the provider organization and validates the provider address against the registry.
+// Validation is done here rather than by validators as the organization may come from the provider.
+// Deleting or replacing a protected provider with published versions fails here, so that it shows in the plan.
+func (r *ProviderRegistryResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var state m.RegistryProvider
+	if !req.State.Raw.IsNull() {
+		diags := req.State.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	if req.Plan.Raw.IsNull() {
+		resp.Diagnostics.Append(r.checkDeletionProtection(ctx, state, "destroy")...)
+		return
+	}
+
+	planOrganization(ctx, r.organization, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan m.RegistryProvider
+	diags := resp.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if plan.DeletionProtection.IsUnknown() && !plan.RegistryName.IsUnknown() {
+		plan.DeletionProtection = types.BoolValue(plan.RegistryName.ValueString() == string(enum.RegistryTypePrivate))
+		diags = resp.Plan.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+	}
+	if !req.State.Raw.IsNull() && registryProviderReplaced(state, plan) {
+		resp.Diagnostics.Append(r.checkDeletionProtection(ctx, state, "replace")...)
+	}
+	if resp.Diagnostics.HasError() || plan.RegistryName.IsUnknown() {
+		return
+	}
+
+	switch enum.RegistryType(plan.RegistryName.ValueString()) {
+	case enum.RegistryTypePrivate:
+		if plan.Organization.IsUnknown() || plan.Namespace.IsUnknown() {
+			return
+		}
+		if !strings.EqualFold(plan.Namespace.ValueString(), plan.Organization.ValueString()) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("namespace"),
+				"Invalid private provider namespace",
+				fmt.Sprintf("The namespace of a private provider must be the organization name, expected %q but got %q.", plan.Organization.ValueString(), plan.Namespace.ValueString()),
+			)
+		}
+	case enum.RegistryTypePublic:
+		if !plan.Namespace.IsUnknown() && !publicNamespacePattern.MatchString(plan.Namespace.ValueString()) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("namespace"),
+				"Invalid public provider namespace",
+				fmt.Sprintf("The namespace %q is not a valid registry namespace: it must be letters, digits and single dashes, neither starting nor ending with a dash.", plan.Namespace.ValueString()),
+			)
+		}
+		if !plan.Name.IsUnknown() && !publicNamePattern.MatchString(plan.Name.ValueString()) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("name"),
+				"Invalid public provider name",
+				fmt.Sprintf("The name %q is not a valid registry provider name: it must be lower case letters, digits and single dashes, neither starting nor ending with a dash.", plan.Name.ValueString()),
+			)
+		} else if strings.HasPrefix(plan.Name.ValueString(), "terraform-provider-") {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("name"),
+				"Invalid public provider name",
+				fmt.Sprintf("The name %q must be the provider type without the terraform-provider- prefix of its repository, i.e. %q.", plan.Name.ValueString(), strings.TrimPrefix(plan.Name.ValueString(), "terraform-provider-")),
+			)
+		}
+	}
+}
+
 func (r *ProviderRegistryResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = recordApiErrors(ctx)
 	var plan m.RegistryProvider
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -104,6 +222,14 @@ func (r *ProviderRegistryResource) Create(ctx context.Context, req resource.Crea
 		return
 	}
 
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
 	cr, err := r.client.ProviderService.Create(ctx, plan.Organization.ValueString(), &apir.Provider{
 		Data: apir.ProviderData{
 			Type: "registry-providers",
@@ -115,11 +241,11 @@ func (r *ProviderRegistryResource) Create(ctx context.Context, req resource.Crea
 		},
 	})
 
+	if err != nil && plan.AdoptExisting.ValueBool() && isConflict(err) {
+		cr, err = r.adoptRegistryProvider(ctx, plan, err, &resp.Diagnostics)
+	}
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error reading resource",
-			"Could not read resource "+err.Error(),
-		)
+		addApiError(ctx, &resp.Diagnostics, "create the registry provider", err, "namespace", "name", "registry_name")
 		return
 	}
 
@@ -131,7 +257,23 @@ func (r *ProviderRegistryResource) Create(ctx context.Context, req resource.Crea
 	}
 }
 
+// adoptRegistryProvider reads the provider that failed to be created because it already exists. The create error is returned when there is no such provider,
+// as TFE answers invalid provider addresses with the same status.
+func (r *ProviderRegistryResource) adoptRegistryProvider(ctx context.Context, plan m.RegistryProvider, createErr error, diags *diag.Diagnostics) (mresp.Provider, error) {
+	rr, err := r.client.ProviderService.Read(ctx, plan.Organization.ValueString(), plan.RegistryName.ValueString(), plan.Namespace.ValueString(), plan.Name.ValueString())
+	if err != nil {
+		return rr, createErr
+	}
+	diags.AddWarning(
+		"Adopted existing registry provider",
+		fmt.Sprintf("The %s registry provider %s/%s already exists in organization %s and has been taken into state rather than created. Destroying the resource deletes it.",
+			rr.Data.Attributes.RegistryName, rr.Data.Attributes.Namespace, rr.Data.Attributes.Name, plan.Organization.ValueString()),
+	)
+	return rr, nil
+}
+
 func (r *ProviderRegistryResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = recordApiErrors(ctx)
 	var state m.RegistryProvider
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -139,22 +281,45 @@ func (r *ProviderRegistryResource) Read(ctx context.Context, req resource.ReadRe
 		return
 	}
 
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
 	rr, err := r.client.ProviderService.Read(ctx, state.Organization.ValueString(), state.RegistryName.ValueString(), state.Namespace.ValueString(), state.Name.ValueString())
-	//TODO: Handle deleted resource read
+	if isNotFound(err) {
+		// Deleted out of band: remove from state so that Terraform plans a recreate
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error reading resource",
-			"Could not read resource "+err.Error(),
-		)
+		addApiError(ctx, &resp.Diagnostics, "read the registry provider", err)
 		return
 	}
 
 	state = m.RegistryProvider{
-		Id:           types.StringValue(fmt.Sprintf(strings.ToLower("%s||%s||%s"), rr.Data.Attributes.Namespace, rr.Data.Attributes.Name, rr.Data.Attributes.RegistryName)),
-		Organization: state.Organization,
-		Namespace:    types.StringValue(rr.Data.Attributes.Namespace),
-		Name:         types.StringValue(rr.Data.Attributes.Name),
-		RegistryName: types.StringValue(string(rr.Data.Attributes.RegistryName)),
+		Id:                 types.StringValue(fmt.Sprintf(strings.ToLower("%s||%s||%s"), rr.Data.Attributes.Namespace, rr.Data.Attributes.Name, rr.Data.Attributes.RegistryName)),
+		Organization:       state.Organization,
+		Namespace:          types.StringValue(rr.Data.Attributes.Namespace),
+		Name:               types.StringValue(rr.Data.Attributes.Name),
+		RegistryName:       types.StringValue(string(rr.Data.Attributes.RegistryName)),
+		DeletionProtection: state.DeletionProtection,
+		ForceDestroy:       state.ForceDestroy,
+		AdoptExisting:      state.AdoptExisting,
+		Timeouts:           state.Timeouts,
+	}
+	// Imported providers get the defaults
+	if state.DeletionProtection.IsNull() {
+		state.DeletionProtection = types.BoolValue(rr.Data.Attributes.RegistryName == enum.RegistryTypePrivate)
+	}
+	if state.ForceDestroy.IsNull() {
+		state.ForceDestroy = types.BoolValue(false)
+	}
+	if state.AdoptExisting.IsNull() {
+		state.AdoptExisting = types.BoolValue(false)
 	}
 
 	diags = resp.State.Set(ctx, &state)
@@ -164,11 +329,27 @@ func (r *ProviderRegistryResource) Read(ctx context.Context, req resource.ReadRe
 	}
 }
 
-// No update as all attributes require replacement if changed
+// Update persists deletion_protection, force_destroy, adopt_existing and the timeouts as all other attributes require replacement if changed
 func (r *ProviderRegistryResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state m.RegistryProvider
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.DeletionProtection = plan.DeletionProtection
+	state.ForceDestroy = plan.ForceDestroy
+	state.AdoptExisting = plan.AdoptExisting
+	state.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
 }
 
 func (r *ProviderRegistryResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = recordApiErrors(ctx)
 	var state m.RegistryProvider
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -176,24 +357,118 @@ func (r *ProviderRegistryResource) Delete(ctx context.Context, req resource.Dele
 		return
 	}
 
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	// The plan has been checked, but versions may have been published since
+	diags = r.checkDeletionProtection(ctx, state, "delete")
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	err := r.client.ProviderService.Delete(ctx, state.Organization.ValueString(), state.RegistryName.ValueString(), state.Namespace.ValueString(), state.Name.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error reading resource",
-			"Could not read resource "+err.Error(),
-		)
+		addApiError(ctx, &resp.Diagnostics, "delete the registry provider", err)
 		return
 	}
 
 	resp.State.RemoveResource(ctx)
 }
 
-// TODO: test import
+// ImportState imports by '<organization>/<registry_name>/<namespace>/<name>'. The id is set to '<namespace>||<name>||<registry_name>' by the read that follows.
+// The former '<namespace>||<name>||<registry_name>' id is still accepted and resolves the organization from the namespace of private providers, else the provider organization.
 func (r *ProviderRegistryResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import ID and save to the attributes that are utilized by the Read-function
-	parts := strings.Split(req.ID, "||")
-	resp.State.SetAttribute(ctx, path.Root("namespace"), parts[0])
-	resp.State.SetAttribute(ctx, path.Root("name"), parts[1])
-	resp.State.SetAttribute(ctx, path.Root("registry_name"), parts[2])
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	var organization, registryName, namespace, name string
+	if parts, ok := splitImportId(req.ID, "/", 4); ok {
+		organization, registryName, namespace, name = parts[0], parts[1], parts[2], parts[3]
+	} else if parts, ok := splitImportId(req.ID, "||", 3); ok {
+		namespace, name, registryName = parts[0], parts[1], parts[2]
+		organization = r.organization
+		if registryName == string(enum.RegistryTypePrivate) {
+			organization = namespace
+		}
+	} else {
+		resp.Diagnostics.AddError(
+			"Invalid import id",
+			fmt.Sprintf("Expected import id on the format '<organization>/<registry_name>/<namespace>/<name>', got: %s", req.ID),
+		)
+		return
+	}
+
+	if registryName != string(enum.RegistryTypePrivate) && registryName != string(enum.RegistryTypePublic) {
+		resp.Diagnostics.AddError(
+			"Invalid import id",
+			fmt.Sprintf("Expected registry_name 'private' or 'public' in import id %s, got: %s", req.ID, registryName),
+		)
+		return
+	}
+	if organization == "" {
+		resp.Diagnostics.AddError(
+			"Missing organization",
+			fmt.Sprintf("The import id %s has no organization and the provider has none configured. Import by '<organization>/<registry_name>/<namespace>/<name>'.", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("organization"), organization)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("namespace"), namespace)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("registry_name"), registryName)...)
+}
+
+// checkDeletionProtection fails when the provider is protected, not forced to be destroyed and has published versions.
+// Only private providers have versions.
+func (r *ProviderRegistryResource) checkDeletionProtection(ctx context.Context, state m.RegistryProvider, action string) diag.Diagnostics {
+	ctx = recordApiErrors(ctx)
+	var diags diag.Diagnostics
+	if !state.DeletionProtection.ValueBool() || state.ForceDestroy.ValueBool() || state.RegistryName.ValueString() != string(enum.RegistryTypePrivate) {
+		return diags
+	}
+
+	versions, err := listRegistryProviderVersions(ctx, r.client, state.Organization.ValueString(), state.Namespace.ValueString(), state.Name.ValueString())
+	if isNotFound(err) {
+		return diags
+	}
+	if err != nil {
+		addApiError(ctx, &diags, "list the versions of the registry provider", err)
+		return diags
+	}
+	if len(versions) == 0 {
+		return diags
+	}
+	published := make([]string, 0, len(versions))
+	for _, version := range versions {
+		published = append(published, version.Attributes.Version)
+	}
+	sort.Strings(published)
+	diags.AddAttributeError(
+		path.Root("deletion_protection"),
+		"Registry provider is protected",
+		fmt.Sprintf("Cannot %s the registry provider %s/%s as deleting it deletes its published versions %s, which breaks every configuration that uses them. "+
+			"Set force_destroy = true and apply before you %s it, or set deletion_protection = false.", action, state.Namespace.ValueString(), state.Name.ValueString(), strings.Join(published, ", "), action),
+	)
+	return diags
+}
+
+// registryProviderReplaced reports whether the plan replaces the provider, i.e. changes an attribute that requires replacement.
+// Unknown values are not known to replace it.
+func registryProviderReplaced(state, plan m.RegistryProvider) bool {
+	for _, attribute := range [][2]types.String{
+		{state.Organization, plan.Organization},
+		{state.Namespace, plan.Namespace},
+		{state.Name, plan.Name},
+		{state.RegistryName, plan.RegistryName},
+	} {
+		if !attribute[1].IsUnknown() && !attribute[0].Equal(attribute[1]) {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,425 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	m "github.com/tsanton/terraform-provider-tfepatch/provider/models"
+	api "github.com/tsanton/tfe-client/tfe"
+	apir "github.com/tsanton/tfe-client/tfe/models/request"
+	apis "github.com/tsanton/tfe-client/tfe/models/response"
+)
+
+type RegistryProviderPlatformResource struct {
+	client       *api.TerraformEnterpriseClient
+	organization string
+	httpClient   *http.Client
+}
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &RegistryProviderPlatformResource{}
+	_ resource.ResourceWithConfigure   = &RegistryProviderPlatformResource{}
+	_ resource.ResourceWithImportState = &RegistryProviderPlatformResource{}
+	_ resource.ResourceWithModifyPlan  = &RegistryProviderPlatformResource{}
+)
+
+// newResource is a helper function to simplify the provider implementation.
+func newRegistryProviderPlatformResource() resource.Resource {
+	return &RegistryProviderPlatformResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *RegistryProviderPlatformResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	data := req.ProviderData.(*providerData)
+	r.client = data.client
+	r.organization = data.organization
+	r.httpClient = data.httpClient
+}
+
+func (r *RegistryProviderPlatformResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				Description:         "Unique id for this resource",
+				MarkdownDescription: "Unique id for this resource",
+			},
+			"shasum": schema.StringAttribute{
+				Computed:            true,
+				Description:         "The sha256 checksum of the provider binary archive. Computed from the local archive at plan time, a change forces replacement",
+				MarkdownDescription: "The sha256 checksum of the provider binary archive. Computed from the local archive at plan time, a change forces replacement",
+			},
+			"provider_binary_uploaded": schema.BoolAttribute{
+				Computed:            true,
+				Description:         "Whether or not the provider binary archive has been uploaded",
+				MarkdownDescription: "Whether or not the provider binary archive has been uploaded",
+			},
+			"provider_binary_upload_url": schema.StringAttribute{
+				Computed:            true,
+				Description:         "The link the provider binary archive is uploaded to. Only set while the archive is yet to be uploaded",
+				MarkdownDescription: "The link the provider binary archive is uploaded to. Only set while the archive is yet to be uploaded",
+			},
+			// Input attributes
+			"organization": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Description:         "The organization name under which the provider registry exists. Defaults to the provider organization",
+				MarkdownDescription: "The organization name under which the provider registry exists. Defaults to the provider organization",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"namespace": schema.StringAttribute{
+				Required:            true,
+				Description:         "The namespace of the private provider registry. Must match the organization name",
+				MarkdownDescription: "The namespace of the private provider registry. Must match the organization name",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:            true,
+				Description:         "The name of the provider",
+				MarkdownDescription: "The name of the provider",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"version": schema.StringAttribute{
+				Required:            true,
+				Description:         "The provider version (tfepatch_registry_provider_version) the platform belongs to",
+				MarkdownDescription: "The provider version (`tfepatch_registry_provider_version`) the platform belongs to",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"os": schema.StringAttribute{
+				Required:            true,
+				Description:         "The operating system of the platform, i.e. 'linux'",
+				MarkdownDescription: "The operating system of the platform, i.e. `linux`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"arch": schema.StringAttribute{
+				Required:            true,
+				Description:         "The architecture of the platform, i.e. 'amd64'",
+				MarkdownDescription: "The architecture of the platform, i.e. `amd64`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"archive_path": schema.StringAttribute{
+				Required:            true,
+				Description:         "The local path to the provider binary zip archive to upload. A path that is only known after apply replaces an existing platform",
+				MarkdownDescription: "The local path to the provider binary zip archive to upload. A path that is only known after apply replaces an existing platform",
+			},
+			"filename": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Description:         "The filename of the provider binary archive. Defaults to the base name of archive_path",
+				MarkdownDescription: "The filename of the provider binary archive. Defaults to the base name of `archive_path`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeoutsOpts),
+		},
+	}
+}
+
+// Metadata returns the resource type name.
+func (r *RegistryProviderPlatformResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_registry_provider_platform"
+}
+
+// ModifyPlan computes the checksum of the local archive so that a change in archive content forces a new platform and upload.
+// The organization defaults to the provider organization.
+func (r *RegistryProviderPlatformResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to compute on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	planOrganization(ctx, r.organization, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan m.RegistryProviderPlatform
+	diags := resp.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The archive may be produced by another resource, in which case the checksum is known after apply.
+	// An existing platform is replaced as its archive may have changed, which Update cannot upload anew.
+	if plan.ArchivePath.IsUnknown() {
+		if !req.State.Raw.IsNull() {
+			resp.RequiresReplace = append(resp.RequiresReplace, path.Root("shasum"))
+			plan.Shasum = types.StringUnknown()
+			plan.ProviderBinaryUploaded = types.BoolUnknown()
+			plan.ProviderBinaryUploadUrl = types.StringUnknown()
+			diags = resp.Plan.Set(ctx, plan)
+			resp.Diagnostics.Append(diags...)
+		}
+		return
+	}
+
+	shasum, err := fileSha256(plan.ArchivePath.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("archive_path"),
+			"Unable to read provider binary archive",
+			"Could not compute the checksum of the provider binary archive "+err.Error(),
+		)
+		return
+	}
+	plan.Shasum = types.StringValue(shasum)
+
+	replace := req.State.Raw.IsNull()
+	if !req.State.Raw.IsNull() {
+		var state m.RegistryProviderPlatform
+		diags = req.State.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if state.Shasum.ValueString() != shasum {
+			resp.RequiresReplace = append(resp.RequiresReplace, path.Root("shasum"))
+			plan.ProviderBinaryUploaded = types.BoolUnknown()
+			plan.ProviderBinaryUploadUrl = types.StringUnknown()
+			replace = true
+		} else if plan.Filename.IsUnknown() {
+			plan.Filename = state.Filename
+		}
+	}
+
+	if replace && plan.Filename.IsUnknown() {
+		plan.Filename = types.StringValue(filepath.Base(plan.ArchivePath.ValueString()))
+	}
+
+	diags = resp.Plan.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *RegistryProviderPlatformResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = recordApiErrors(ctx)
+	var plan m.RegistryProviderPlatform
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	// The archive path may have been unknown during plan
+	shasum, err := fileSha256(plan.ArchivePath.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("archive_path"),
+			"Unable to read provider binary archive",
+			"Could not compute the checksum of the provider binary archive "+err.Error(),
+		)
+		return
+	}
+	if !plan.Shasum.IsUnknown() && plan.Shasum.ValueString() != shasum {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("archive_path"),
+			"Provider binary archive changed after plan",
+			fmt.Sprintf("The planned checksum %s does not match the checksum %s of the archive. Re-run the plan", plan.Shasum.ValueString(), shasum),
+		)
+		return
+	}
+	if plan.Filename.IsUnknown() {
+		plan.Filename = types.StringValue(filepath.Base(plan.ArchivePath.ValueString()))
+	}
+
+	cr, err := r.client.ProviderVersionPlatformService.Create(ctx, plan.Organization.ValueString(), plan.Namespace.ValueString(), plan.Name.ValueString(), plan.Version.ValueString(), &apir.ProviderVersionPlatform{
+		Data: apir.ProviderVersionPlatformData{
+			Type: "registry-provider-version-platforms",
+			Attributes: apir.ProviderVersionPlatformDataAttributes{
+				Os:      plan.Os.ValueString(),
+				Arch:    plan.Arch.ValueString(),
+				Shasum:  shasum,
+				Filname: plan.Filename.ValueString(),
+			},
+		},
+	})
+	if err != nil {
+		addApiError(ctx, &resp.Diagnostics, "create the provider platform", err, "os", "arch", "filename")
+		return
+	}
+
+	// The platform is persisted before the upload so that a failed upload leaves a tainted resource rather than an orphaned platform
+	plan = registryProviderPlatformState(plan, cr.Data)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err = uploadFile(ctx, r.httpClient, cr.Data.Links.ProviderBinaryUpload, plan.ArchivePath.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("archive_path"),
+			"Error uploading provider binary",
+			"Could not upload provider binary "+err.Error(),
+		)
+		return
+	}
+
+	// Re-read the platform to pick up the upload state
+	rr, err := r.client.ProviderVersionPlatformService.Read(ctx, plan.Organization.ValueString(), plan.Namespace.ValueString(), plan.Name.ValueString(), plan.Version.ValueString(), plan.Os.ValueString(), plan.Arch.ValueString())
+	if err != nil {
+		addApiError(ctx, &resp.Diagnostics, "read the provider platform", err)
+		return
+	}
+
+	plan = registryProviderPlatformState(plan, rr.Data)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *RegistryProviderPlatformResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = recordApiErrors(ctx)
+	var state m.RegistryProviderPlatform
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	rr, err := r.client.ProviderVersionPlatformService.Read(ctx, state.Organization.ValueString(), state.Namespace.ValueString(), state.Name.ValueString(), state.Version.ValueString(), state.Os.ValueString(), state.Arch.ValueString())
+	if isNotFound(err) {
+		// Deleted out of band: remove from state so that Terraform plans a recreate
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addApiError(ctx, &resp.Diagnostics, "read the provider platform", err)
+		return
+	}
+
+	state = registryProviderPlatformState(state, rr.Data)
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update only persists a moved archive_path with unchanged content and the timeouts as every other change requires replacement
+func (r *RegistryProviderPlatformResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state m.RegistryProviderPlatform
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.ArchivePath = plan.ArchivePath
+	state.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *RegistryProviderPlatformResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = recordApiErrors(ctx)
+	var state m.RegistryProviderPlatform
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	err := r.client.ProviderVersionPlatformService.Delete(ctx, state.Organization.ValueString(), state.Namespace.ValueString(), state.Name.ValueString(), state.Version.ValueString(), state.Os.ValueString(), state.Arch.ValueString())
+	if err != nil {
+		addApiError(ctx, &resp.Diagnostics, "delete the provider platform", err)
+		return
+	}
+
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *RegistryProviderPlatformResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts, ok := splitImportId(req.ID, "/", 6)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Invalid import id",
+			fmt.Sprintf("Expected import id on the format '<organization>/<namespace>/<name>/<version>/<os>/<arch>', got: %s", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("organization"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("namespace"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), parts[2])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("version"), parts[3])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("os"), parts[4])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("arch"), parts[5])...)
+}
+
+// registryProviderPlatformState maps the API response onto the resource model, keeping the local only attributes of the prior model.
+func registryProviderPlatformState(prior m.RegistryProviderPlatform, data apis.ProviderVersionPlatformData) m.RegistryProviderPlatform {
+	return m.RegistryProviderPlatform{
+		Id:                      types.StringValue(fmt.Sprintf("%s||%s||%s||%s||%s||%s", prior.Organization.ValueString(), prior.Namespace.ValueString(), prior.Name.ValueString(), prior.Version.ValueString(), data.Attributes.Os, data.Attributes.Arch)),
+		Organization:            prior.Organization,
+		Namespace:               prior.Namespace,
+		Name:                    prior.Name,
+		Version:                 prior.Version,
+		Os:                      types.StringValue(data.Attributes.Os),
+		Arch:                    types.StringValue(data.Attributes.Arch),
+		Filename:                types.StringValue(data.Attributes.Filename),
+		ArchivePath:             prior.ArchivePath,
+		Shasum:                  types.StringValue(data.Attributes.Shasum),
+		ProviderBinaryUploaded:  types.BoolValue(data.Attributes.ProviderBinaryUploaded),
+		ProviderBinaryUploadUrl: stringValueOrNull(data.Links.ProviderBinaryUpload),
+		Timeouts:                prior.Timeouts,
+	}
+}
@@ -0,0 +1,325 @@
+package provider_test
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/assert"
+	m "github.com/tsanton/terraform-provider-tfepatch/provider/models"
+	u "github.com/tsanton/terraform-provider-tfepatch/utilities"
+)
+
+func Test_provider_registry_provider_platform(t *testing.T) {
+	useCassette(t)
+	/* Arrange */
+	t.Log("Arranging")
+	orgName := u.GetEnv("TFE_ORG_NAME", "")
+	namespace := orgName
+	name := "demo-provider"
+	version := "0.0.2"
+	dir := t.TempDir()
+	entity := loadCassetteGpgEntity(t)
+	publicKey, err := generateGpgKey(entity)
+	if err != nil {
+		t.Log("Unable to generate GPG key")
+		t.FailNow()
+	}
+	archivePrefix := fmt.Sprintf("terraform-provider-%s_%s", name, version)
+	archivePath, err := generateProviderArchive(dir, archivePrefix+"_linux_amd64.zip", "v1")
+	if err != nil {
+		t.Log("Unable to generate provider archive")
+		t.FailNow()
+	}
+	shasumsPath, shasumsSigPath, err := generateShasums(dir, entity, archivePrefix, archivePath)
+	if err != nil {
+		t.Log("Unable to generate SHA256SUMS")
+		t.FailNow()
+	}
+	config := providerConfig + fmt.Sprintf(`
+	resource "tfepatch_registry_provider" "this" {
+		organization  = "%[1]s"
+		namespace     = "%[2]s"
+		name          = "%[3]s"
+		registry_name = "private"
+		force_destroy = true
+	  }
+
+	resource "tfepatch_gpg_key" "this" {
+		organization = "%[1]s"
+		namespace    = "%[2]s"
+		public_key   = trimspace(<<EOF
+%[4]s
+EOF
+)
+	  }
+
+	resource "tfepatch_registry_provider_version" "this" {
+		organization     = tfepatch_registry_provider.this.organization
+		namespace        = tfepatch_registry_provider.this.namespace
+		name             = tfepatch_registry_provider.this.name
+		version          = "%[5]s"
+		key_id           = tfepatch_gpg_key.this.key_id
+		protocols        = ["6.0"]
+		shasums_path     = "%[6]s"
+		shasums_sig_path = "%[7]s"
+	  }
+
+	resource "tfepatch_registry_provider_platform" "this" {
+		organization = tfepatch_registry_provider_version.this.organization
+		namespace    = tfepatch_registry_provider_version.this.namespace
+		name         = tfepatch_registry_provider_version.this.name
+		version      = tfepatch_registry_provider_version.this.version
+		os           = "linux"
+		arch         = "amd64"
+		archive_path = "%[8]s"
+	  }
+	`, orgName, namespace, name, publicKey, version, shasumsPath, shasumsSigPath, archivePath)
+
+	/* Act */
+	log.Println("Invoking tests")
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			//--------------------------------------------------------------------------
+			//--- Create and Read testing
+			//--------------------------------------------------------------------------
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_platform.this", "version", version),
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_platform.this", "os", "linux"),
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_platform.this", "arch", "amd64"),
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_platform.this", "filename", filepath.Base(archivePath)),
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_platform.this", "shasum", archiveShasum(t, archivePath)),
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_platform.this", "provider_binary_uploaded", "true"),
+				),
+			},
+			//--------------------------------------------------------------------------
+			//--- Content change triggers a new platform and upload
+			//--------------------------------------------------------------------------
+			{
+				PreConfig: func() {
+					_, err := generateProviderArchive(dir, filepath.Base(archivePath), "v2")
+					assert.Nil(t, err)
+				},
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("tfepatch_registry_provider_platform.this", "shasum", func(value string) error {
+						if value != archiveShasum(t, archivePath) {
+							return fmt.Errorf("expected shasum of the rebuilt archive, got %s", value)
+						}
+						return nil
+					}),
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_platform.this", "provider_binary_uploaded", "true"),
+				),
+			},
+			{
+				RefreshState: true,
+				PreConfig: func() {
+					platform, err := cli.ProviderVersionPlatformService.Read(context.Background(), orgName, namespace, name, version, "linux", "amd64")
+					assert.Nil(t, err)
+					assert.Equal(t, archiveShasum(t, archivePath), platform.Data.Attributes.Shasum)
+					assert.True(t, platform.Data.Attributes.ProviderBinaryUploaded)
+				},
+			},
+		},
+	})
+}
+
+// generateProviderArchive writes a zip archive holding a dummy provider binary with the given content to dir.
+func generateProviderArchive(dir, filename, content string) (string, error) {
+	archivePath := filepath.Join(dir, filename)
+	f, err := os.Create(archivePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	binary, err := w.Create("terraform-provider-demo")
+	if err != nil {
+		return "", err
+	}
+	if _, err = binary.Write([]byte(content)); err != nil {
+		return "", err
+	}
+	if err = w.Close(); err != nil {
+		return "", err
+	}
+	return archivePath, nil
+}
+
+func archiveShasum(t *testing.T, archivePath string) string {
+	content, err := os.ReadFile(archivePath)
+	assert.Nil(t, err)
+	return fmt.Sprintf("%x", sha256.Sum256(content))
+}
+
+func Test_registry_provider_platform_lifecycle(t *testing.T) {
+	/* Arrange */
+	requireTerraformCli(t)
+	fake := newFakeTfeApi(t)
+	dir := t.TempDir()
+	entity := newTestGpgEntity(t, time.Now(), 0)
+	archivePath, err := generateProviderArchive(dir, "terraform-provider-demo-provider_1.0.0_linux_amd64.zip", "v1")
+	assert.Nil(t, err)
+	shasumsPath, shasumsSigPath, err := generateShasums(dir, entity, "terraform-provider-demo-provider_1.0.0", archivePath)
+	assert.Nil(t, err)
+
+	/* Act */
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fake.providerConfig() + fmt.Sprintf(`
+				resource "tfepatch_registry_provider" "this" {
+					namespace     = "test-org"
+					name          = "demo-provider"
+					registry_name = "private"
+					force_destroy = true
+				  }
+
+				resource "tfepatch_gpg_key" "this" {
+					namespace  = "test-org"
+					public_key = trimspace(<<EOF
+%s
+EOF
+)
+				  }
+
+				resource "tfepatch_registry_provider_version" "this" {
+					namespace        = tfepatch_registry_provider.this.namespace
+					name             = tfepatch_registry_provider.this.name
+					version          = "1.0.0"
+					key_id           = tfepatch_gpg_key.this.key_id
+					protocols        = ["6.0"]
+					shasums_path     = "%s"
+					shasums_sig_path = "%s"
+				  }
+
+				resource "tfepatch_registry_provider_platform" "this" {
+					namespace    = tfepatch_registry_provider_version.this.namespace
+					name         = tfepatch_registry_provider_version.this.name
+					version      = tfepatch_registry_provider_version.this.version
+					os           = "linux"
+					arch         = "amd64"
+					archive_path = "%s"
+				  }
+				`, armoredGpgKeys(t, openpgp.PublicKeyType, entity), shasumsPath, shasumsSigPath, archivePath),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_platform.this", "filename", filepath.Base(archivePath)),
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_platform.this", "shasum", archiveShasum(t, archivePath)),
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_platform.this", "provider_binary_uploaded", "true"),
+				),
+			},
+			{
+				ResourceName:            "tfepatch_registry_provider_platform.this",
+				ImportState:             true,
+				ImportStateId:           "test-org/test-org/demo-provider/1.0.0/linux/amd64",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"archive_path"},
+			},
+		},
+	})
+}
+
+func Test_registry_provider_platform_import_id(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		err  string
+	}{
+		{name: "organization qualified", id: "test-org/test-org/demo-provider/1.0.0/linux/amd64"},
+		{name: "legacy separator", id: "test-org||test-org||demo-provider||1.0.0||linux||amd64", err: "Invalid import id"},
+		{name: "too few parts", id: "test-org/test-org/demo-provider/1.0.0/linux", err: "Invalid import id"},
+		{name: "empty part", id: "test-org/test-org/demo-provider/1.0.0//amd64", err: "Invalid import id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* Arrange */
+			r := newTestResource(t, "tfepatch_registry_provider_platform", testProviderValues("localhost"))
+
+			/* Act */
+			resp := importTestState(r, tt.id)
+
+			/* Assert */
+			if tt.err != "" {
+				assert.True(t, resp.Diagnostics.HasError())
+				assert.Equal(t, tt.err, resp.Diagnostics.Errors()[0].Summary())
+				return
+			}
+			assert.False(t, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+			var state m.RegistryProviderPlatform
+			assert.False(t, resp.State.Get(context.Background(), &state).HasError())
+			assert.Equal(t, "test-org", state.Organization.ValueString())
+			assert.Equal(t, "demo-provider", state.Name.ValueString())
+			assert.Equal(t, "1.0.0", state.Version.ValueString())
+			assert.Equal(t, "linux", state.Os.ValueString())
+			assert.Equal(t, "amd64", state.Arch.ValueString())
+		})
+	}
+}
+
+func Test_registry_provider_platform_plan_of_existing_platform(t *testing.T) {
+	dir := t.TempDir()
+	archivePath, err := generateProviderArchive(dir, "terraform-provider-demo-provider_1.0.0_linux_amd64.zip", "v1")
+	assert.Nil(t, err)
+	tests := []struct {
+		name        string
+		archivePath tftypes.Value
+		replace     bool
+	}{
+		{name: "unchanged archive", archivePath: tftypes.NewValue(tftypes.String, archivePath)},
+		{name: "archive known after apply", archivePath: tftypes.NewValue(tftypes.String, tftypes.UnknownValue), replace: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* Arrange */
+			r := newTestResource(t, "tfepatch_registry_provider_platform", testProviderValues("localhost"))
+			req := newTestModifyPlanRequest(t, r, map[string]tftypes.Value{
+				"namespace":    tftypes.NewValue(tftypes.String, "test-org"),
+				"name":         tftypes.NewValue(tftypes.String, "demo-provider"),
+				"version":      tftypes.NewValue(tftypes.String, "1.0.0"),
+				"os":           tftypes.NewValue(tftypes.String, "linux"),
+				"arch":         tftypes.NewValue(tftypes.String, "amd64"),
+				"archive_path": tt.archivePath,
+			}, &m.RegistryProviderPlatform{
+				Id:                      types.StringValue("test-org||test-org||demo-provider||1.0.0||linux||amd64"),
+				Organization:            types.StringValue("test-org"),
+				Namespace:               types.StringValue("test-org"),
+				Name:                    types.StringValue("demo-provider"),
+				Version:                 types.StringValue("1.0.0"),
+				Os:                      types.StringValue("linux"),
+				Arch:                    types.StringValue("amd64"),
+				ArchivePath:             types.StringValue(archivePath),
+				Filename:                types.StringValue(filepath.Base(archivePath)),
+				Shasum:                  types.StringValue(archiveShasum(t, archivePath)),
+				ProviderBinaryUploaded:  types.BoolValue(true),
+				ProviderBinaryUploadUrl: types.StringNull(),
+			})
+			resp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+
+			/* Act */
+			r.(fwresource.ResourceWithModifyPlan).ModifyPlan(context.Background(), req, &resp)
+
+			/* Assert */
+			assert.False(t, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+			assert.Equal(t, tt.replace, len(resp.RequiresReplace) > 0)
+			var plan m.RegistryProviderPlatform
+			assert.False(t, resp.Plan.Get(context.Background(), &plan).HasError())
+			assert.Equal(t, tt.replace, plan.Shasum.IsUnknown())
+		})
+	}
+}
@@ -0,0 +1,561 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	m "github.com/tsanton/terraform-provider-tfepatch/provider/models"
+	api "github.com/tsanton/tfe-client/tfe"
+	apir "github.com/tsanton/tfe-client/tfe/models/request"
+)
+
+type RegistryProviderReleaseResource struct {
+	client       *api.TerraformEnterpriseClient
+	organization string
+	httpClient   *http.Client
+}
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                     = &RegistryProviderReleaseResource{}
+	_ resource.ResourceWithConfigure        = &RegistryProviderReleaseResource{}
+	_ resource.ResourceWithImportState      = &RegistryProviderReleaseResource{}
+	_ resource.ResourceWithModifyPlan       = &RegistryProviderReleaseResource{}
+	_ resource.ResourceWithConfigValidators = &RegistryProviderReleaseResource{}
+)
+
+var registryProviderReleasePlatformAttrTypes = map[string]attr.Type{
+	"os":                       types.StringType,
+	"arch":                     types.StringType,
+	"filename":                 types.StringType,
+	"shasum":                   types.StringType,
+	"provider_binary_uploaded": types.BoolType,
+}
+
+// newResource is a helper function to simplify the provider implementation.
+func newRegistryProviderReleaseResource() resource.Resource {
+	return &RegistryProviderReleaseResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *RegistryProviderReleaseResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	data := req.ProviderData.(*providerData)
+	r.client = data.client
+	r.organization = data.organization
+	r.httpClient = data.httpClient
+}
+
+func (r *RegistryProviderReleaseResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				Description:         "Unique id for this resource",
+				MarkdownDescription: "Unique id for this resource",
+			},
+			"shasums_sha256": schema.StringAttribute{
+				Computed:            true,
+				Description:         "The sha256 checksum of the SHA256SUMS file. Computed from the dist directory at plan time, a change forces a new release",
+				MarkdownDescription: "The sha256 checksum of the SHA256SUMS file. Computed from the dist directory at plan time, a change forces a new release",
+			},
+			"shasums_uploaded": schema.BoolAttribute{
+				Computed:            true,
+				Description:         "Whether or not the SHA256SUMS file has been uploaded",
+				MarkdownDescription: "Whether or not the SHA256SUMS file has been uploaded",
+			},
+			"shasums_sig_uploaded": schema.BoolAttribute{
+				Computed:            true,
+				Description:         "Whether or not the SHA256SUMS.sig file has been uploaded",
+				MarkdownDescription: "Whether or not the SHA256SUMS.sig file has been uploaded",
+			},
+			"platforms": schema.MapNestedAttribute{
+				Computed:            true,
+				Description:         "The platforms of the release keyed by '<os>_<arch>'. The registry holds one archive per platform, so the release fails on several goarm or goamd64 variants of a platform",
+				MarkdownDescription: "The platforms of the release keyed by `<os>_<arch>`. The registry holds one archive per platform, so the release fails on several `goarm` or `goamd64` variants of a platform",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"os": schema.StringAttribute{
+							Computed:            true,
+							Description:         "The operating system of the platform",
+							MarkdownDescription: "The operating system of the platform",
+						},
+						"arch": schema.StringAttribute{
+							Computed:            true,
+							Description:         "The architecture of the platform",
+							MarkdownDescription: "The architecture of the platform",
+						},
+						"filename": schema.StringAttribute{
+							Computed:            true,
+							Description:         "The filename of the provider binary archive",
+							MarkdownDescription: "The filename of the provider binary archive",
+						},
+						"shasum": schema.StringAttribute{
+							Computed:            true,
+							Description:         "The sha256 checksum of the provider binary archive",
+							MarkdownDescription: "The sha256 checksum of the provider binary archive",
+						},
+						"provider_binary_uploaded": schema.BoolAttribute{
+							Computed:            true,
+							Description:         "Whether or not the provider binary archive has been uploaded",
+							MarkdownDescription: "Whether or not the provider binary archive has been uploaded",
+						},
+					},
+				},
+			},
+			// Input attributes
+			"organization": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Description:         "The organization name under which the provider registry exists. Defaults to the provider organization",
+				MarkdownDescription: "The organization name under which the provider registry exists. Defaults to the provider organization",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"namespace": schema.StringAttribute{
+				Required:            true,
+				Description:         "The namespace of the private provider registry. Must match the organization name",
+				MarkdownDescription: "The namespace of the private provider registry. Must match the organization name",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:            true,
+				Description:         "The name of the provider",
+				MarkdownDescription: "The name of the provider",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"key_id": schema.StringAttribute{
+				Required:            true,
+				Description:         "The key_id of the GPG key (tfepatch_gpg_key) that the SHA256SUMS file is signed with",
+				MarkdownDescription: "The key_id of the GPG key (`tfepatch_gpg_key`) that the SHA256SUMS file is signed with",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"protocols": schema.ListAttribute{
+				Required:            true,
+				ElementType:         types.StringType,
+				Description:         "The Terraform plugin protocol versions the provider version supports, i.e. ['5.0'] or ['6.0']",
+				MarkdownDescription: "The Terraform plugin protocol versions the provider version supports, i.e. `[\"5.0\"]` or `[\"6.0\"]`",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ValueStringsAre(stringvalidator.OneOf("4.0", "5.0", "6.0")),
+				},
+			},
+			"version": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Description:         "The semantic version of the release. Defaults to the version in the goreleaser metadata.json",
+				MarkdownDescription: "The semantic version of the release. Defaults to the version in the goreleaser `metadata.json`",
+			},
+			"dist_dir": schema.StringAttribute{
+				Optional:            true,
+				Description:         "The goreleaser dist directory holding artifacts.json, metadata.json, the archives, SHA256SUMS and its signature. Conflicts with artifacts_path",
+				MarkdownDescription: "The goreleaser dist directory holding `artifacts.json`, `metadata.json`, the archives, SHA256SUMS and its signature. Conflicts with `artifacts_path`",
+			},
+			"artifacts_path": schema.StringAttribute{
+				Optional:            true,
+				Description:         "The path to the goreleaser artifacts.json. The artifacts are resolved relative to its directory. Conflicts with dist_dir",
+				MarkdownDescription: "The path to the goreleaser `artifacts.json`. The artifacts are resolved relative to its directory. Conflicts with `dist_dir`",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeoutsOpts),
+		},
+	}
+}
+
+func (r *RegistryProviderReleaseResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("dist_dir"),
+			path.MatchRoot("artifacts_path"),
+		),
+	}
+}
+
+// Metadata returns the resource type name.
+func (r *RegistryProviderReleaseResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_registry_provider_release"
+}
+
+// ModifyPlan resolves the release from the dist directory so that a new version or changed artifacts force a new release.
+// The organization defaults to the provider organization.
+func (r *RegistryProviderReleaseResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to resolve on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	planOrganization(ctx, r.organization, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan m.RegistryProviderRelease
+	diags := resp.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	var configVersion types.String
+	diags = req.Config.GetAttribute(ctx, path.Root("version"), &configVersion)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The dist directory may be produced by another resource, in which case the release is resolved during apply
+	if plan.DistDir.IsUnknown() || plan.ArtifactsPath.IsUnknown() || configVersion.IsUnknown() {
+		return
+	}
+
+	release, err := readGoreleaserRelease(plan.DistDir.ValueString(), plan.ArtifactsPath.ValueString(), configVersion.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read goreleaser release",
+			"Could not resolve the release from the goreleaser artifacts "+err.Error(),
+		)
+		return
+	}
+	shasumsSha256, err := fileSha256(release.ShasumsPath)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read SHA256SUMS",
+			"Could not compute the checksum of SHA256SUMS "+err.Error(),
+		)
+		return
+	}
+	plan.Version = types.StringValue(release.Version)
+	plan.ShasumsSha256 = types.StringValue(shasumsSha256)
+
+	if !req.State.Raw.IsNull() {
+		var state m.RegistryProviderRelease
+		diags = req.State.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if state.Version.ValueString() != release.Version {
+			resp.RequiresReplace = append(resp.RequiresReplace, path.Root("version"))
+		}
+		// An imported release has no known checksum and is adopted as is
+		if !state.ShasumsSha256.IsNull() && state.ShasumsSha256.ValueString() != shasumsSha256 {
+			resp.RequiresReplace = append(resp.RequiresReplace, path.Root("shasums_sha256"))
+		}
+		if len(resp.RequiresReplace) > 0 {
+			plan.Id = types.StringUnknown()
+			plan.ShasumsUploaded = types.BoolUnknown()
+			plan.ShasumsSigUploaded = types.BoolUnknown()
+			plan.Platforms = types.MapUnknown(types.ObjectType{AttrTypes: registryProviderReleasePlatformAttrTypes})
+		}
+	}
+
+	diags = resp.Plan.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *RegistryProviderReleaseResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = recordApiErrors(ctx)
+	var plan m.RegistryProviderRelease
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	var protocols []string
+	diags = plan.Protocols.ElementsAs(ctx, &protocols, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The dist directory may have been unknown during plan
+	release, err := readGoreleaserRelease(plan.DistDir.ValueString(), plan.ArtifactsPath.ValueString(), plan.Version.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read goreleaser release",
+			"Could not resolve the release from the goreleaser artifacts "+err.Error(),
+		)
+		return
+	}
+	shasumsSha256, err := fileSha256(release.ShasumsPath)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to read SHA256SUMS",
+			"Could not compute the checksum of SHA256SUMS "+err.Error(),
+		)
+		return
+	}
+	if !plan.ShasumsSha256.IsUnknown() && plan.ShasumsSha256.ValueString() != shasumsSha256 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("dist_dir"),
+			"SHA256SUMS changed after plan",
+			fmt.Sprintf("The planned checksum %s does not match the checksum %s of the SHA256SUMS file. Re-run the plan", plan.ShasumsSha256.ValueString(), shasumsSha256),
+		)
+		return
+	}
+	plan.Version = types.StringValue(release.Version)
+	plan.ShasumsSha256 = types.StringValue(shasumsSha256)
+
+	cr, err := r.client.ProviderVersionService.Create(ctx, plan.Organization.ValueString(), plan.Namespace.ValueString(), plan.Name.ValueString(), &apir.ProviderVersion{
+		Data: apir.ProviderVersionData{
+			Type: "registry-provider-versions",
+			Attributes: apir.ProviderVersionDataAttributes{
+				Version:   release.Version,
+				KeyId:     plan.KeyId.ValueString(),
+				Protocols: protocols,
+			},
+		},
+	})
+	if err != nil {
+		addApiError(ctx, &resp.Diagnostics, "create the provider version", err, "key_id", "protocols")
+		return
+	}
+
+	// The version is persisted before the uploads so that a failed upload leaves a tainted resource rather than an orphaned version
+	plan.Id = types.StringValue(fmt.Sprintf("%s||%s||%s||%s", plan.Organization.ValueString(), plan.Namespace.ValueString(), plan.Name.ValueString(), release.Version))
+	plan.ShasumsUploaded = types.BoolValue(cr.Data.Attributes.ShasumsUploaded)
+	plan.ShasumsSigUploaded = types.BoolValue(cr.Data.Attributes.ShasumsSigUploaded)
+	plan.Platforms = types.MapValueMust(types.ObjectType{AttrTypes: registryProviderReleasePlatformAttrTypes}, map[string]attr.Value{})
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err = uploadFile(ctx, r.httpClient, cr.Data.Links.ShasumsUploadUrl, release.ShasumsPath); err != nil {
+		resp.Diagnostics.AddError(
+			"Error uploading SHA256SUMS",
+			"Could not upload SHA256SUMS "+err.Error(),
+		)
+		return
+	}
+	if err = uploadFile(ctx, r.httpClient, cr.Data.Links.ShasumsSigUploadUrl, release.ShasumsSigPath); err != nil {
+		resp.Diagnostics.AddError(
+			"Error uploading SHA256SUMS.sig",
+			"Could not upload SHA256SUMS.sig "+err.Error(),
+		)
+		return
+	}
+
+	for _, platform := range release.Platforms {
+		key := platformKey(platform.Os, platform.Arch)
+		shasum, err := fileSha256(platform.ArchivePath)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to read provider binary archive",
+				fmt.Sprintf("Could not compute the checksum of the %s archive %s", key, err.Error()),
+			)
+			return
+		}
+
+		pr, err := r.client.ProviderVersionPlatformService.Create(ctx, plan.Organization.ValueString(), plan.Namespace.ValueString(), plan.Name.ValueString(), release.Version, &apir.ProviderVersionPlatform{
+			Data: apir.ProviderVersionPlatformData{
+				Type: "registry-provider-version-platforms",
+				Attributes: apir.ProviderVersionPlatformDataAttributes{
+					Os:      platform.Os,
+					Arch:    platform.Arch,
+					Shasum:  shasum,
+					Filname: platform.Filename,
+				},
+			},
+		})
+		if err != nil {
+			addApiError(ctx, &resp.Diagnostics, "create the "+key+" provider platform", err)
+			return
+		}
+
+		if err = uploadFile(ctx, r.httpClient, pr.Data.Links.ProviderBinaryUpload, platform.ArchivePath); err != nil {
+			resp.Diagnostics.AddError(
+				"Error uploading provider binary",
+				fmt.Sprintf("Could not upload the %s provider binary %s", key, err.Error()),
+			)
+			return
+		}
+	}
+
+	// Re-read the release to pick up the upload state of the version and every platform
+	plan, _, diags = r.read(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *RegistryProviderReleaseResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = recordApiErrors(ctx)
+	var state m.RegistryProviderRelease
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	state, found, diags := r.read(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !found {
+		// Deleted out of band: remove from state so that Terraform plans a recreate
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update only persists moved dist directories and the timeouts as every other change requires replacement
+func (r *RegistryProviderReleaseResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state m.RegistryProviderRelease
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.DistDir = plan.DistDir
+	state.ArtifactsPath = plan.ArtifactsPath
+	state.Timeouts = plan.Timeouts
+	if !plan.ShasumsSha256.IsUnknown() {
+		state.ShasumsSha256 = plan.ShasumsSha256
+	}
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *RegistryProviderReleaseResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = recordApiErrors(ctx)
+	var state m.RegistryProviderRelease
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	// Deleting the version deletes every platform of the version
+	err := r.client.ProviderVersionService.Delete(ctx, state.Organization.ValueString(), state.Namespace.ValueString(), state.Name.ValueString(), state.Version.ValueString())
+	if err != nil {
+		addApiError(ctx, &resp.Diagnostics, "delete the provider version", err)
+		return
+	}
+
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *RegistryProviderReleaseResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts, ok := splitImportId(req.ID, "/", 4)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Invalid import id",
+			fmt.Sprintf("Expected import id on the format '<organization>/<namespace>/<name>/<version>', got: %s", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("organization"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("namespace"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), parts[2])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("version"), parts[3])...)
+}
+
+// read refreshes the version and platform attributes of the release from the API, reporting whether the version still exists.
+func (r *RegistryProviderReleaseResource) read(ctx context.Context, state m.RegistryProviderRelease) (m.RegistryProviderRelease, bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	organization, namespace, name, version := state.Organization.ValueString(), state.Namespace.ValueString(), state.Name.ValueString(), state.Version.ValueString()
+
+	vr, err := r.client.ProviderVersionService.Read(ctx, organization, namespace, name, version)
+	if isNotFound(err) {
+		return state, false, diags
+	}
+	if err != nil {
+		addApiError(ctx, &diags, "read the provider version", err)
+		return state, false, diags
+	}
+
+	pr, err := r.client.ProviderVersionPlatformService.List(ctx, organization, namespace, name, version)
+	if err != nil {
+		addApiError(ctx, &diags, "list the provider platforms", err)
+		return state, false, diags
+	}
+
+	platforms := map[string]m.RegistryProviderReleasePlatform{}
+	for _, platform := range pr.Data {
+		platforms[platformKey(platform.Attributes.Os, platform.Attributes.Arch)] = m.RegistryProviderReleasePlatform{
+			Os:                     types.StringValue(platform.Attributes.Os),
+			Arch:                   types.StringValue(platform.Attributes.Arch),
+			Filename:               types.StringValue(platform.Attributes.Filename),
+			Shasum:                 types.StringValue(platform.Attributes.Shasum),
+			ProviderBinaryUploaded: types.BoolValue(platform.Attributes.ProviderBinaryUploaded),
+		}
+	}
+
+	protocols, d := types.ListValueFrom(ctx, types.StringType, vr.Data.Attributes.Protocols)
+	diags.Append(d...)
+	platformsValue, d := types.MapValueFrom(ctx, types.ObjectType{AttrTypes: registryProviderReleasePlatformAttrTypes}, platforms)
+	diags.Append(d...)
+
+	state.Id = types.StringValue(fmt.Sprintf("%s||%s||%s||%s", organization, namespace, name, vr.Data.Attributes.Version))
+	state.Version = types.StringValue(vr.Data.Attributes.Version)
+	state.KeyId = types.StringValue(vr.Data.Attributes.KeyId)
+	state.Protocols = protocols
+	state.ShasumsUploaded = types.BoolValue(vr.Data.Attributes.ShasumsUploaded)
+	state.ShasumsSigUploaded = types.BoolValue(vr.Data.Attributes.ShasumsSigUploaded)
+	state.Platforms = platformsValue
+	return state, true, diags
+}
@@ -0,0 +1,421 @@
+package provider_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/assert"
+	m "github.com/tsanton/terraform-provider-tfepatch/provider/models"
+	u "github.com/tsanton/terraform-provider-tfepatch/utilities"
+)
+
+func Test_provider_registry_provider_release(t *testing.T) {
+	useCassette(t)
+	/* Arrange */
+	t.Log("Arranging")
+	orgName := u.GetEnv("TFE_ORG_NAME", "")
+	namespace := orgName
+	name := "demo-provider"
+	version := "0.0.3"
+	entity := loadCassetteGpgEntity(t)
+	publicKey, err := generateGpgKey(entity)
+	if err != nil {
+		t.Log("Unable to generate GPG key")
+		t.FailNow()
+	}
+	distDir, err := generateGoreleaserDist(t.TempDir(), entity, name, version, "linux_amd64", "darwin_arm64")
+	if err != nil {
+		t.Log("Unable to generate goreleaser dist directory")
+		t.FailNow()
+	}
+
+	/* Act */
+	log.Println("Invoking tests")
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			//--------------------------------------------------------------------------
+			//--- Create and Read testing
+			//--------------------------------------------------------------------------
+			{
+				Config: providerConfig + fmt.Sprintf(`
+				resource "tfepatch_registry_provider" "this" {
+					organization  = "%[1]s"
+					namespace     = "%[2]s"
+					name          = "%[3]s"
+					registry_name = "private"
+					force_destroy = true
+				  }
+
+				resource "tfepatch_gpg_key" "this" {
+					organization = "%[1]s"
+					namespace    = "%[2]s"
+					public_key   = trimspace(<<EOF
+%[4]s
+EOF
+)
+				  }
+
+				resource "tfepatch_registry_provider_release" "this" {
+					organization = tfepatch_registry_provider.this.organization
+					namespace    = tfepatch_registry_provider.this.namespace
+					name         = tfepatch_registry_provider.this.name
+					key_id       = tfepatch_gpg_key.this.key_id
+					protocols    = ["6.0"]
+					dist_dir     = "%[5]s"
+				  }
+				`, orgName, namespace, name, publicKey, distDir),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_release.this", "version", version),
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_release.this", "shasums_uploaded", "true"),
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_release.this", "shasums_sig_uploaded", "true"),
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_release.this", "platforms.%", "2"),
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_release.this", "platforms.linux_amd64.provider_binary_uploaded", "true"),
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_release.this", "platforms.darwin_arm64.provider_binary_uploaded", "true"),
+				),
+			},
+			{
+				RefreshState: true,
+				PreConfig: func() {
+					platforms, err := cli.ProviderVersionPlatformService.List(context.Background(), orgName, namespace, name, version)
+					assert.Nil(t, err)
+					assert.Equal(t, 2, len(platforms.Data))
+				},
+			},
+		},
+	})
+}
+
+// generateGoreleaserDist writes a goreleaser like dist directory (archives, SHA256SUMS, signature, artifacts.json and metadata.json) to dir.
+func generateGoreleaserDist(dir string, entity *openpgp.Entity, name, version string, platforms ...string) (string, error) {
+	type artifact struct {
+		Name   string `json:"name"`
+		Path   string `json:"path"`
+		Goos   string `json:"goos,omitempty"`
+		Goarch string `json:"goarch,omitempty"`
+		Type   string `json:"type"`
+	}
+	prefix := fmt.Sprintf("terraform-provider-%s_%s", name, version)
+
+	var artifacts []artifact
+	var archivePaths []string
+	for _, platform := range platforms {
+		goos, goarch, _ := strings.Cut(platform, "_")
+		filename := fmt.Sprintf("%s_%s.zip", prefix, platform)
+		archivePath, err := generateProviderArchive(dir, filename, platform)
+		if err != nil {
+			return "", err
+		}
+		archivePaths = append(archivePaths, archivePath)
+		artifacts = append(artifacts, artifact{Name: filename, Path: "dist/" + filename, Goos: goos, Goarch: goarch, Type: "Archive"})
+	}
+
+	shasumsPath, shasumsSigPath, err := generateShasums(dir, entity, prefix, archivePaths...)
+	if err != nil {
+		return "", err
+	}
+	artifacts = append(artifacts,
+		artifact{Name: filepath.Base(shasumsPath), Path: "dist/" + filepath.Base(shasumsPath), Type: "Checksum"},
+		artifact{Name: filepath.Base(shasumsSigPath), Path: "dist/" + filepath.Base(shasumsSigPath), Type: "Signature"},
+	)
+
+	content, err := json.Marshal(artifacts)
+	if err != nil {
+		return "", err
+	}
+	if err = os.WriteFile(filepath.Join(dir, "artifacts.json"), content, 0o600); err != nil {
+		return "", err
+	}
+	metadata := fmt.Sprintf(`{"project_name":"terraform-provider-%s","tag":"v%s","version":"%s"}`, name, version, version)
+	if err = os.WriteFile(filepath.Join(dir, "metadata.json"), []byte(metadata), 0o600); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+func Test_registry_provider_release_lifecycle(t *testing.T) {
+	/* Arrange */
+	requireTerraformCli(t)
+	fake := newFakeTfeApi(t)
+	entity := newTestGpgEntity(t, time.Now(), 0)
+	distDir, err := generateGoreleaserDist(t.TempDir(), entity, "demo-provider", "1.0.0", "linux_amd64", "darwin_arm64")
+	assert.Nil(t, err)
+
+	/* Act */
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fake.providerConfig() + fmt.Sprintf(`
+				resource "tfepatch_registry_provider" "this" {
+					namespace     = "test-org"
+					name          = "demo-provider"
+					registry_name = "private"
+					force_destroy = true
+				  }
+
+				resource "tfepatch_gpg_key" "this" {
+					namespace  = "test-org"
+					public_key = trimspace(<<EOF
+%s
+EOF
+)
+				  }
+
+				resource "tfepatch_registry_provider_release" "this" {
+					namespace = tfepatch_registry_provider.this.namespace
+					name      = tfepatch_registry_provider.this.name
+					key_id    = tfepatch_gpg_key.this.key_id
+					protocols = ["6.0"]
+					dist_dir  = "%s"
+				  }
+				`, armoredGpgKeys(t, openpgp.PublicKeyType, entity), distDir),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_release.this", "version", "1.0.0"),
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_release.this", "shasums_uploaded", "true"),
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_release.this", "platforms.%", "2"),
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_release.this", "platforms.linux_amd64.provider_binary_uploaded", "true"),
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_release.this", "platforms.darwin_arm64.provider_binary_uploaded", "true"),
+				),
+			},
+		},
+	})
+}
+
+func Test_registry_provider_release_import_id(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		err  string
+	}{
+		{name: "organization qualified", id: "test-org/test-org/demo-provider/1.0.0"},
+		{name: "legacy separator", id: "test-org||test-org||demo-provider||1.0.0", err: "Invalid import id"},
+		{name: "too many parts", id: "test-org/test-org/demo-provider/1.0.0/linux", err: "Invalid import id"},
+		{name: "empty part", id: "test-org/test-org//1.0.0", err: "Invalid import id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* Arrange */
+			r := newTestResource(t, "tfepatch_registry_provider_release", testProviderValues("localhost"))
+
+			/* Act */
+			resp := importTestState(r, tt.id)
+
+			/* Assert */
+			if tt.err != "" {
+				assert.True(t, resp.Diagnostics.HasError())
+				assert.Equal(t, tt.err, resp.Diagnostics.Errors()[0].Summary())
+				return
+			}
+			assert.False(t, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+			var state m.RegistryProviderRelease
+			assert.False(t, resp.State.Get(context.Background(), &state).HasError())
+			assert.Equal(t, "test-org", state.Organization.ValueString())
+			assert.Equal(t, "test-org", state.Namespace.ValueString())
+			assert.Equal(t, "demo-provider", state.Name.ValueString())
+			assert.Equal(t, "1.0.0", state.Version.ValueString())
+		})
+	}
+}
+
+func Test_registry_provider_release_uploads_the_checksum_signature(t *testing.T) {
+	tests := []struct {
+		name              string
+		checksumSignature bool
+		err               string
+	}{
+		{name: "signed archives", checksumSignature: true},
+		{name: "unsigned checksum", err: "Unable to read goreleaser release"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* Arrange */
+			fake := newFakeTfeApi(t)
+			providerValues := testProviderValues(fake.server.URL)
+			providerValues["token"] = tftypes.NewValue(tftypes.String, fakeToken)
+			create := func(resourceType string, configValues map[string]tftypes.Value) fwresource.CreateResponse {
+				return planAndCreateTestResource(t, providerValues, resourceType, configValues, nil)
+			}
+			entity := newTestGpgEntity(t, time.Now(), 0)
+			distDir, err := generateGoreleaserDist(t.TempDir(), entity, "demo-provider", "1.0.0", "linux_amd64", "darwin_arm64")
+			assert.Nil(t, err)
+			// goreleaser lists the archive signatures after the checksum signature when signing all artifacts
+			signGoreleaserArchives(t, distDir, tt.checksumSignature)
+			provider := create("tfepatch_registry_provider", map[string]tftypes.Value{
+				"namespace":     tftypes.NewValue(tftypes.String, fakeOrganization),
+				"name":          tftypes.NewValue(tftypes.String, "demo-provider"),
+				"registry_name": tftypes.NewValue(tftypes.String, "private"),
+			})
+			assert.False(t, provider.Diagnostics.HasError(), "%v", provider.Diagnostics)
+			gpgKey := create("tfepatch_gpg_key", map[string]tftypes.Value{
+				"namespace":  tftypes.NewValue(tftypes.String, fakeOrganization),
+				"public_key": tftypes.NewValue(tftypes.String, armoredGpgKeys(t, openpgp.PublicKeyType, entity)),
+			})
+			assert.False(t, gpgKey.Diagnostics.HasError(), "%v", gpgKey.Diagnostics)
+			var key m.GpgKey
+			assert.False(t, gpgKey.State.Get(context.Background(), &key).HasError())
+
+			/* Act */
+			resp := create("tfepatch_registry_provider_release", map[string]tftypes.Value{
+				"namespace": tftypes.NewValue(tftypes.String, fakeOrganization),
+				"name":      tftypes.NewValue(tftypes.String, "demo-provider"),
+				"key_id":    tftypes.NewValue(tftypes.String, key.KeyId.ValueString()),
+				"protocols": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{tftypes.NewValue(tftypes.String, "6.0")}),
+				"dist_dir":  tftypes.NewValue(tftypes.String, distDir),
+			})
+
+			/* Assert */
+			if tt.err != "" {
+				assert.Equal(t, 1, resp.Diagnostics.ErrorsCount())
+				assert.Equal(t, tt.err, resp.Diagnostics.Errors()[0].Summary())
+				assert.Contains(t, resp.Diagnostics.Errors()[0].Detail(), "terraform-provider-demo-provider_1.0.0_SHA256SUMS.sig")
+				return
+			}
+			assert.False(t, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+			registryProvider := fake.registryProvider("private", fakeOrganization, "demo-provider")
+			shasumsSig, err := os.ReadFile(filepath.Join(distDir, "terraform-provider-demo-provider_1.0.0_SHA256SUMS.sig"))
+			assert.Nil(t, err)
+			if assert.Len(t, registryProvider.versions, 1) {
+				assert.Equal(t, shasumsSig, registryProvider.versions[0].shasumsSig)
+			}
+		})
+	}
+}
+
+// signGoreleaserArchives adds a signature of every archive to the artifacts of the goreleaser dist directory,
+// keeping the signature of the checksum file unless keepChecksumSignature is false.
+func signGoreleaserArchives(t *testing.T, distDir string, keepChecksumSignature bool) {
+	artifactsPath := filepath.Join(distDir, "artifacts.json")
+	content, err := os.ReadFile(artifactsPath)
+	assert.Nil(t, err)
+	var artifacts []map[string]string
+	assert.Nil(t, json.Unmarshal(content, &artifacts))
+
+	var signed []map[string]string
+	for _, artifact := range artifacts {
+		if artifact["type"] != "Signature" || keepChecksumSignature {
+			signed = append(signed, artifact)
+		}
+	}
+	for _, artifact := range artifacts {
+		if artifact["type"] != "Archive" {
+			continue
+		}
+		name := artifact["name"] + ".sig"
+		assert.Nil(t, os.WriteFile(filepath.Join(distDir, name), []byte("archive signature"), 0o600))
+		signed = append(signed, map[string]string{"name": name, "path": "dist/" + name, "type": "Signature"})
+	}
+	content, err = json.Marshal(signed)
+	assert.Nil(t, err)
+	assert.Nil(t, os.WriteFile(artifactsPath, content, 0o600))
+}
+
+// planAndCreateTestResource plans the resource with the config values and creates it, running betweenPlanAndApply in between if set.
+func planAndCreateTestResource(t *testing.T, providerValues map[string]tftypes.Value, resourceType string, configValues map[string]tftypes.Value, betweenPlanAndApply func()) fwresource.CreateResponse {
+	r := newTestResource(t, resourceType, providerValues)
+	req := newTestModifyPlanRequest(t, r, configValues, nil)
+	planResp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+	r.(fwresource.ResourceWithModifyPlan).ModifyPlan(context.Background(), req, &planResp)
+	resp := fwresource.CreateResponse{State: tfsdk.State{Schema: req.State.Schema, Raw: req.State.Raw}, Diagnostics: planResp.Diagnostics}
+	if planResp.Diagnostics.HasError() {
+		return resp
+	}
+	if betweenPlanAndApply != nil {
+		betweenPlanAndApply()
+	}
+	r.Create(context.Background(), fwresource.CreateRequest{Config: req.Config, Plan: planResp.Plan}, &resp)
+	return resp
+}
+
+func Test_registry_provider_release_shasums_changed_after_plan(t *testing.T) {
+	/* Arrange */
+	fake := newFakeTfeApi(t)
+	providerValues := testProviderValues(fake.server.URL)
+	providerValues["token"] = tftypes.NewValue(tftypes.String, fakeToken)
+	entity := newTestGpgEntity(t, time.Now(), 0)
+	distDir, err := generateGoreleaserDist(t.TempDir(), entity, "demo-provider", "1.0.0", "linux_amd64")
+	assert.Nil(t, err)
+	provider := planAndCreateTestResource(t, providerValues, "tfepatch_registry_provider", map[string]tftypes.Value{
+		"namespace":     tftypes.NewValue(tftypes.String, fakeOrganization),
+		"name":          tftypes.NewValue(tftypes.String, "demo-provider"),
+		"registry_name": tftypes.NewValue(tftypes.String, "private"),
+	}, nil)
+	assert.False(t, provider.Diagnostics.HasError(), "%v", provider.Diagnostics)
+	gpgKey := planAndCreateTestResource(t, providerValues, "tfepatch_gpg_key", map[string]tftypes.Value{
+		"namespace":  tftypes.NewValue(tftypes.String, fakeOrganization),
+		"public_key": tftypes.NewValue(tftypes.String, armoredGpgKeys(t, openpgp.PublicKeyType, entity)),
+	}, nil)
+	assert.False(t, gpgKey.Diagnostics.HasError(), "%v", gpgKey.Diagnostics)
+	var key m.GpgKey
+	assert.False(t, gpgKey.State.Get(context.Background(), &key).HasError())
+
+	/* Act */
+	resp := planAndCreateTestResource(t, providerValues, "tfepatch_registry_provider_release", map[string]tftypes.Value{
+		"namespace": tftypes.NewValue(tftypes.String, fakeOrganization),
+		"name":      tftypes.NewValue(tftypes.String, "demo-provider"),
+		"key_id":    tftypes.NewValue(tftypes.String, key.KeyId.ValueString()),
+		"protocols": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{tftypes.NewValue(tftypes.String, "6.0")}),
+		"dist_dir":  tftypes.NewValue(tftypes.String, distDir),
+	}, func() {
+		// goreleaser was run anew between plan and apply
+		_, err := generateGoreleaserDist(distDir, entity, "demo-provider", "1.0.0", "linux_amd64", "darwin_arm64")
+		assert.Nil(t, err)
+	})
+
+	/* Assert */
+	assert.Equal(t, 1, resp.Diagnostics.ErrorsCount())
+	assert.Equal(t, "SHA256SUMS changed after plan", resp.Diagnostics.Errors()[0].Summary())
+	assert.Empty(t, fake.registryProvider("private", fakeOrganization, "demo-provider").versions)
+}
+
+func Test_registry_provider_release_platform_variants(t *testing.T) {
+	/* Arrange */
+	entity := newTestGpgEntity(t, time.Now(), 0)
+	distDir, err := generateGoreleaserDist(t.TempDir(), entity, "demo-provider", "1.0.0", "linux_arm")
+	assert.Nil(t, err)
+	// goreleaser builds an archive of every goarm variant, i.e. armv6 and armv7
+	artifactsPath := filepath.Join(distDir, "artifacts.json")
+	content, err := os.ReadFile(artifactsPath)
+	assert.Nil(t, err)
+	var artifacts []map[string]string
+	assert.Nil(t, json.Unmarshal(content, &artifacts))
+	for _, artifact := range artifacts {
+		if artifact["type"] == "Archive" {
+			artifact["goarm"] = "6"
+			artifacts = append(artifacts, map[string]string{"name": "terraform-provider-demo-provider_1.0.0_linux_armv7.zip", "path": "dist/terraform-provider-demo-provider_1.0.0_linux_armv7.zip", "goos": "linux", "goarch": "arm", "goarm": "7", "type": "Archive"})
+			break
+		}
+	}
+	content, err = json.Marshal(artifacts)
+	assert.Nil(t, err)
+	assert.Nil(t, os.WriteFile(artifactsPath, content, 0o600))
+	r := newTestResource(t, "tfepatch_registry_provider_release", testProviderValues("localhost"))
+	req := newTestModifyPlanRequest(t, r, map[string]tftypes.Value{
+		"namespace": tftypes.NewValue(tftypes.String, "test-org"),
+		"name":      tftypes.NewValue(tftypes.String, "demo-provider"),
+		"key_id":    tftypes.NewValue(tftypes.String, "0123456789ABCDEF"),
+		"protocols": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{tftypes.NewValue(tftypes.String, "6.0")}),
+		"dist_dir":  tftypes.NewValue(tftypes.String, distDir),
+	}, nil)
+	resp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+
+	/* Act */
+	r.(fwresource.ResourceWithModifyPlan).ModifyPlan(context.Background(), req, &resp)
+
+	/* Assert */
+	assert.Equal(t, 1, resp.Diagnostics.ErrorsCount())
+	assert.Equal(t, "Unable to read goreleaser release", resp.Diagnostics.Errors()[0].Summary())
+	assert.Contains(t, resp.Diagnostics.Errors()[0].Detail(), "terraform-provider-demo-provider_1.0.0_linux_arm.zip (goarm 6) and terraform-provider-demo-provider_1.0.0_linux_armv7.zip (goarm 7) for the linux_arm platform")
+}
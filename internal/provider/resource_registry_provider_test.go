@@ -4,16 +4,28 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/stretchr/testify/assert"
+	m "github.com/tsanton/terraform-provider-tfepatch/provider/models"
 	u "github.com/tsanton/terraform-provider-tfepatch/utilities"
 
 	me "github.com/tsanton/tfe-client/tfe/models/enum"
 )
 
 func Test_provider_public_registry_provider(t *testing.T) {
+	useCassette(t)
 	/* Arrange */
 	log.Println("Arranging")
 	orgName := u.GetEnv("TFE_ORG_NAME", "")
@@ -45,6 +57,15 @@ func Test_provider_public_registry_provider(t *testing.T) {
 					resource.TestCheckResourceAttr("tfepatch_registry_provider.this", "registry_name", string(registryType)),
 				),
 			},
+			//--------------------------------------------------------------------------
+			//--- Import testing
+			//--------------------------------------------------------------------------
+			{
+				ResourceName:      "tfepatch_registry_provider.this",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%s/%s/%s/%s", orgName, registryType, namespace, name),
+				ImportStateVerify: true,
+			},
 			{
 				RefreshState: true,
 				// Destroy: true,
@@ -61,6 +82,7 @@ func Test_provider_public_registry_provider(t *testing.T) {
 }
 
 func Test_provider_private_registry_provider(t *testing.T) {
+	useCassette(t)
 	/* Arrange */
 	log.Println("Arranging")
 	orgName := u.GetEnv("TFE_ORG_NAME", "")
@@ -94,6 +116,15 @@ func Test_provider_private_registry_provider(t *testing.T) {
 					resource.TestCheckResourceAttr("tfepatch_registry_provider.this", "registry_name", string(registryType)),
 				),
 			},
+			//--------------------------------------------------------------------------
+			//--- Import testing
+			//--------------------------------------------------------------------------
+			{
+				ResourceName:      "tfepatch_registry_provider.this",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%s/%s/%s/%s", orgName, registryType, namespace, name),
+				ImportStateVerify: true,
+			},
 			{
 				RefreshState: true,
 				// Destroy: true,
@@ -108,3 +139,416 @@ func Test_provider_private_registry_provider(t *testing.T) {
 		},
 	})
 }
+
+func Test_registry_provider_deleted_out_of_band(t *testing.T) {
+	/* Arrange */
+	server := newTestApiServer(t, notFoundHandler)
+	r := newTestResource(t, "tfepatch_registry_provider", testProviderValues(server.URL))
+	state := newTestState(t, r, &m.RegistryProvider{
+		Id:           types.StringValue("test-org||demo-provider||private"),
+		Organization: types.StringValue("test-org"),
+		Namespace:    types.StringValue("test-org"),
+		Name:         types.StringValue("demo-provider"),
+		RegistryName: types.StringValue("private"),
+	})
+	resp := fwresource.ReadResponse{State: state}
+
+	/* Act */
+	r.Read(context.Background(), fwresource.ReadRequest{State: state}, &resp)
+
+	/* Assert */
+	assert.False(t, resp.Diagnostics.HasError())
+	assert.True(t, resp.State.Raw.IsNull())
+}
+
+func Test_registry_provider_read_error(t *testing.T) {
+	/* Arrange */
+	server := newTestApiServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	r := newTestResource(t, "tfepatch_registry_provider", testProviderValues(server.URL))
+	state := newTestState(t, r, &m.RegistryProvider{
+		Id:           types.StringValue("test-org||demo-provider||private"),
+		Organization: types.StringValue("test-org"),
+		Namespace:    types.StringValue("test-org"),
+		Name:         types.StringValue("demo-provider"),
+		RegistryName: types.StringValue("private"),
+	})
+	resp := fwresource.ReadResponse{State: state}
+
+	/* Act */
+	r.Read(context.Background(), fwresource.ReadRequest{State: state}, &resp)
+
+	/* Assert */
+	assert.True(t, resp.Diagnostics.HasError())
+	assert.False(t, resp.State.Raw.IsNull())
+}
+
+func Test_registry_provider_plan_defaults_to_provider_organization(t *testing.T) {
+	/* Arrange */
+	server := newTestApiServer(t, notFoundHandler)
+	r := newTestResource(t, "tfepatch_registry_provider", testProviderValues(server.URL))
+	req := newTestModifyPlanRequest(t, r, map[string]tftypes.Value{
+		"namespace":     tftypes.NewValue(tftypes.String, "test-org"),
+		"name":          tftypes.NewValue(tftypes.String, "demo-provider"),
+		"registry_name": tftypes.NewValue(tftypes.String, "private"),
+	}, nil)
+	resp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+
+	/* Act */
+	r.(fwresource.ResourceWithModifyPlan).ModifyPlan(context.Background(), req, &resp)
+
+	/* Assert */
+	assert.False(t, resp.Diagnostics.HasError())
+	var organization types.String
+	resp.Plan.GetAttribute(context.Background(), path.Root("organization"), &organization)
+	assert.Equal(t, "test-org", organization.ValueString())
+	assert.Empty(t, resp.RequiresReplace)
+}
+
+func Test_registry_provider_plan_replaces_on_provider_organization_change(t *testing.T) {
+	/* Arrange */
+	server := newTestApiServer(t, notFoundHandler)
+	r := newTestResource(t, "tfepatch_registry_provider", testProviderValues(server.URL))
+	req := newTestModifyPlanRequest(t, r, map[string]tftypes.Value{
+		"namespace":     tftypes.NewValue(tftypes.String, "test-org"),
+		"name":          tftypes.NewValue(tftypes.String, "demo-provider"),
+		"registry_name": tftypes.NewValue(tftypes.String, "private"),
+	}, &m.RegistryProvider{
+		Id:           types.StringValue("old-org||demo-provider||private"),
+		Organization: types.StringValue("old-org"),
+		Namespace:    types.StringValue("test-org"),
+		Name:         types.StringValue("demo-provider"),
+		RegistryName: types.StringValue("private"),
+	})
+	resp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+
+	/* Act */
+	r.(fwresource.ResourceWithModifyPlan).ModifyPlan(context.Background(), req, &resp)
+
+	/* Assert */
+	assert.False(t, resp.Diagnostics.HasError())
+	assert.Contains(t, resp.RequiresReplace, path.Root("organization"))
+}
+
+func Test_registry_provider_plan_without_organization(t *testing.T) {
+	/* Arrange */
+	server := newTestApiServer(t, notFoundHandler)
+	providerValues := testProviderValues(server.URL)
+	delete(providerValues, "organization")
+	t.Setenv("TFE_ORGANIZATION", "")
+	r := newTestResource(t, "tfepatch_registry_provider", providerValues)
+	req := newTestModifyPlanRequest(t, r, map[string]tftypes.Value{
+		"namespace":     tftypes.NewValue(tftypes.String, "test-org"),
+		"name":          tftypes.NewValue(tftypes.String, "demo-provider"),
+		"registry_name": tftypes.NewValue(tftypes.String, "private"),
+	}, nil)
+	resp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+
+	/* Act */
+	r.(fwresource.ResourceWithModifyPlan).ModifyPlan(context.Background(), req, &resp)
+
+	/* Assert */
+	assert.True(t, resp.Diagnostics.HasError())
+	assert.Equal(t, "Missing organization", resp.Diagnostics.Errors()[0].Summary())
+}
+
+func Test_registry_provider_plan_validates_address(t *testing.T) {
+	tests := []struct {
+		name         string
+		namespace    string
+		providerName string
+		registryName string
+		err          string
+	}{
+		{name: "private namespace is organization", namespace: "test-org", providerName: "demo-provider", registryName: "private"},
+		{name: "private namespace ignores case", namespace: "Test-Org", providerName: "demo-provider", registryName: "private"},
+		{name: "private namespace is not organization", namespace: "other-org", providerName: "demo-provider", registryName: "private", err: "Invalid private provider namespace"},
+		{name: "public address", namespace: "hashicorp", providerName: "aws", registryName: "public"},
+		{name: "public namespace with underscore", namespace: "hashi_corp", providerName: "aws", registryName: "public", err: "Invalid public provider namespace"},
+		{name: "public namespace with double dash", namespace: "hashi--corp", providerName: "aws", registryName: "public", err: "Invalid public provider namespace"},
+		{name: "public name with upper case", namespace: "hashicorp", providerName: "AWS", registryName: "public", err: "Invalid public provider name"},
+		{name: "public name ending with dash", namespace: "hashicorp", providerName: "aws-", registryName: "public", err: "Invalid public provider name"},
+		{name: "public name with repository prefix", namespace: "hashicorp", providerName: "terraform-provider-aws", registryName: "public", err: "Invalid public provider name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* Arrange */
+			server := newTestApiServer(t, notFoundHandler)
+			r := newTestResource(t, "tfepatch_registry_provider", testProviderValues(server.URL))
+			req := newTestModifyPlanRequest(t, r, map[string]tftypes.Value{
+				"namespace":     tftypes.NewValue(tftypes.String, tt.namespace),
+				"name":          tftypes.NewValue(tftypes.String, tt.providerName),
+				"registry_name": tftypes.NewValue(tftypes.String, tt.registryName),
+			}, nil)
+			resp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+
+			/* Act */
+			r.(fwresource.ResourceWithModifyPlan).ModifyPlan(context.Background(), req, &resp)
+
+			/* Assert */
+			if tt.err == "" {
+				assert.False(t, resp.Diagnostics.HasError())
+				return
+			}
+			assert.True(t, resp.Diagnostics.HasError())
+			assert.Equal(t, tt.err, resp.Diagnostics.Errors()[0].Summary())
+		})
+	}
+}
+
+func Test_registry_provider_import_id(t *testing.T) {
+	tests := []struct {
+		name                                            string
+		id                                              string
+		organization, registryName, namespace, provName string
+		err                                             string
+	}{
+		{name: "organization qualified", id: "test-org/public/hashicorp/aws", organization: "test-org", registryName: "public", namespace: "hashicorp", provName: "aws"},
+		{name: "legacy private", id: "other-org||demo-provider||private", organization: "other-org", registryName: "private", namespace: "other-org", provName: "demo-provider"},
+		{name: "legacy public", id: "hashicorp||aws||public", organization: "test-org", registryName: "public", namespace: "hashicorp", provName: "aws"},
+		{name: "too few parts", id: "test-org/private/demo-provider", err: "Invalid import id"},
+		{name: "empty part", id: "test-org//test-org/demo-provider", err: "Invalid import id"},
+		{name: "unknown registry", id: "test-org/internal/test-org/demo-provider", err: "Invalid import id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* Arrange */
+			server := newTestApiServer(t, notFoundHandler)
+			r := newTestResource(t, "tfepatch_registry_provider", testProviderValues(server.URL))
+
+			/* Act */
+			resp := importTestState(r, tt.id)
+
+			/* Assert */
+			if tt.err != "" {
+				assert.True(t, resp.Diagnostics.HasError())
+				assert.Equal(t, tt.err, resp.Diagnostics.Errors()[0].Summary())
+				return
+			}
+			assert.False(t, resp.Diagnostics.HasError())
+			var state m.RegistryProvider
+			resp.State.Get(context.Background(), &state)
+			assert.Equal(t, tt.organization, state.Organization.ValueString())
+			assert.Equal(t, tt.registryName, state.RegistryName.ValueString())
+			assert.Equal(t, tt.namespace, state.Namespace.ValueString())
+			assert.Equal(t, tt.provName, state.Name.ValueString())
+		})
+	}
+}
+
+func Test_registry_provider_import_legacy_public_id_without_organization(t *testing.T) {
+	/* Arrange */
+	server := newTestApiServer(t, notFoundHandler)
+	providerValues := testProviderValues(server.URL)
+	delete(providerValues, "organization")
+	t.Setenv("TFE_ORGANIZATION", "")
+	r := newTestResource(t, "tfepatch_registry_provider", providerValues)
+
+	/* Act */
+	resp := importTestState(r, "hashicorp||aws||public")
+
+	/* Assert */
+	assert.True(t, resp.Diagnostics.HasError())
+	assert.Equal(t, "Missing organization", resp.Diagnostics.Errors()[0].Summary())
+}
+
+func Test_registry_provider_lifecycle(t *testing.T) {
+	/* Arrange */
+	requireTerraformCli(t)
+	fake := newFakeTfeApi(t)
+
+	/* Act */
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(_ *terraform.State) error {
+			if fake.registryProvider("private", fakeOrganization, "demo-provider") != nil {
+				return fmt.Errorf("registry provider demo-provider was not deleted")
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fake.providerConfig() + `
+				resource "tfepatch_registry_provider" "this" {
+					namespace     = "test-org"
+					name          = "demo-provider"
+					registry_name = "private"
+				  }
+				`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("tfepatch_registry_provider.this", "organization", fakeOrganization),
+					resource.TestCheckResourceAttr("tfepatch_registry_provider.this", "namespace", fakeOrganization),
+					func(_ *terraform.State) error {
+						if fake.registryProvider("private", fakeOrganization, "demo-provider") == nil {
+							return fmt.Errorf("registry provider demo-provider was not created")
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:      "tfepatch_registry_provider.this",
+				ImportState:       true,
+				ImportStateId:     "test-org/private/test-org/demo-provider",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// newProtectedProviderApiServer serves a private provider with versions 1.0.0 and 1.1.0, recording the requests to delete it.
+func newProtectedProviderApiServer(t *testing.T) (*httptest.Server, *int) {
+	deletes := 0
+	server := newTestApiServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/registry-providers/private/test-org/demo-provider/versions"):
+			_, _ = w.Write([]byte(`{"data":[` +
+				`{"id":"provver-1","type":"registry-provider-versions","attributes":{"version":"1.1.0","key-id":"0123456789ABCDEF"}},` +
+				`{"id":"provver-2","type":"registry-provider-versions","attributes":{"version":"1.0.0","key-id":"0123456789ABCDEF"}}` +
+				`],"meta":{"pagination":{"current-page":1,"total-pages":1,"total-count":2}}}`))
+		case r.Method == http.MethodDelete:
+			deletes++
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			notFoundHandler(w, r)
+		}
+	})
+	return server, &deletes
+}
+
+func Test_registry_provider_deletion_protection(t *testing.T) {
+	tests := []struct {
+		name               string
+		deletionProtection bool
+		forceDestroy       bool
+		protected          bool
+	}{
+		{name: "protected", deletionProtection: true, protected: true},
+		{name: "forced", deletionProtection: true, forceDestroy: true},
+		{name: "unprotected", deletionProtection: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* Arrange */
+			server, deletes := newProtectedProviderApiServer(t)
+			r := newTestResource(t, "tfepatch_registry_provider", testProviderValues(server.URL))
+			state := newTestState(t, r, &m.RegistryProvider{
+				Id:                 types.StringValue("test-org||demo-provider||private"),
+				Organization:       types.StringValue("test-org"),
+				Namespace:          types.StringValue("test-org"),
+				Name:               types.StringValue("demo-provider"),
+				RegistryName:       types.StringValue("private"),
+				DeletionProtection: types.BoolValue(tt.deletionProtection),
+				ForceDestroy:       types.BoolValue(tt.forceDestroy),
+			})
+			planReq := fwresource.ModifyPlanRequest{State: state, Plan: tfsdk.Plan{Schema: state.Schema, Raw: tftypes.NewValue(state.Raw.Type(), nil)}}
+			planResp := fwresource.ModifyPlanResponse{Plan: planReq.Plan}
+			deleteResp := fwresource.DeleteResponse{State: state}
+
+			/* Act */
+			r.(fwresource.ResourceWithModifyPlan).ModifyPlan(context.Background(), planReq, &planResp)
+			r.Delete(context.Background(), fwresource.DeleteRequest{State: state}, &deleteResp)
+
+			/* Assert */
+			if !tt.protected {
+				assert.False(t, planResp.Diagnostics.HasError())
+				assert.False(t, deleteResp.Diagnostics.HasError())
+				assert.Equal(t, 1, *deletes)
+				return
+			}
+			for _, diags := range []diag.Diagnostics{planResp.Diagnostics, deleteResp.Diagnostics} {
+				assert.Equal(t, 1, diags.ErrorsCount())
+				assert.Equal(t, "Registry provider is protected", diags.Errors()[0].Summary())
+				assert.Contains(t, diags.Errors()[0].Detail(), "published versions 1.0.0, 1.1.0")
+			}
+			assert.Contains(t, planResp.Diagnostics.Errors()[0].Detail(), "Cannot destroy")
+			assert.Equal(t, 0, *deletes)
+			assert.False(t, deleteResp.State.Raw.IsNull())
+		})
+	}
+}
+
+func Test_registry_provider_deletion_protection_on_replace(t *testing.T) {
+	/* Arrange */
+	server, _ := newProtectedProviderApiServer(t)
+	r := newTestResource(t, "tfepatch_registry_provider", testProviderValues(server.URL))
+	req := newTestModifyPlanRequest(t, r, map[string]tftypes.Value{
+		"namespace":     tftypes.NewValue(tftypes.String, "test-org"),
+		"name":          tftypes.NewValue(tftypes.String, "renamed-provider"),
+		"registry_name": tftypes.NewValue(tftypes.String, "private"),
+		"force_destroy": tftypes.NewValue(tftypes.Bool, false),
+	}, &m.RegistryProvider{
+		Id:                 types.StringValue("test-org||demo-provider||private"),
+		Organization:       types.StringValue("test-org"),
+		Namespace:          types.StringValue("test-org"),
+		Name:               types.StringValue("demo-provider"),
+		RegistryName:       types.StringValue("private"),
+		DeletionProtection: types.BoolValue(true),
+		ForceDestroy:       types.BoolValue(false),
+	})
+	resp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+
+	/* Act */
+	r.(fwresource.ResourceWithModifyPlan).ModifyPlan(context.Background(), req, &resp)
+
+	/* Assert */
+	assert.Equal(t, 1, resp.Diagnostics.ErrorsCount())
+	assert.Contains(t, resp.Diagnostics.Errors()[0].Detail(), "Cannot replace the registry provider test-org/demo-provider")
+	var deletionProtection types.Bool
+	resp.Plan.GetAttribute(context.Background(), path.Root("deletion_protection"), &deletionProtection)
+	assert.True(t, deletionProtection.ValueBool())
+}
+
+func Test_registry_provider_adopt_existing(t *testing.T) {
+	tests := []struct {
+		name    string
+		adopt   bool
+		summary string
+	}{
+		{name: "adopts the existing provider", adopt: true},
+		{name: "fails without adopt_existing", adopt: false, summary: "Invalid TFE request"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* Arrange */
+			fake := newFakeTfeApi(t)
+			providerValues := testProviderValues(fake.server.URL)
+			providerValues["token"] = tftypes.NewValue(tftypes.String, fakeToken)
+			r := newTestResource(t, "tfepatch_registry_provider", providerValues)
+			create := func(adopt bool) fwresource.CreateResponse {
+				req := newTestModifyPlanRequest(t, r, map[string]tftypes.Value{
+					"namespace":      tftypes.NewValue(tftypes.String, fakeOrganization),
+					"name":           tftypes.NewValue(tftypes.String, "demo-provider"),
+					"registry_name":  tftypes.NewValue(tftypes.String, "private"),
+					"adopt_existing": tftypes.NewValue(tftypes.Bool, adopt),
+				}, nil)
+				planResp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+				r.(fwresource.ResourceWithModifyPlan).ModifyPlan(context.Background(), req, &planResp)
+				resp := fwresource.CreateResponse{State: tfsdk.State{Schema: req.State.Schema, Raw: req.State.Raw}}
+				r.Create(context.Background(), fwresource.CreateRequest{Config: req.Config, Plan: planResp.Plan}, &resp)
+				return resp
+			}
+			existing := create(false)
+			assert.False(t, existing.Diagnostics.HasError(), "%v", existing.Diagnostics)
+
+			/* Act */
+			resp := create(tt.adopt)
+
+			/* Assert */
+			if tt.summary != "" {
+				assert.Equal(t, 1, resp.Diagnostics.ErrorsCount())
+				assert.Equal(t, tt.summary, resp.Diagnostics.Errors()[0].Summary())
+				return
+			}
+			assert.False(t, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+			assert.Equal(t, 1, resp.Diagnostics.WarningsCount())
+			assert.Equal(t, "Adopted existing registry provider", resp.Diagnostics.Warnings()[0].Summary())
+			var state m.RegistryProvider
+			assert.False(t, resp.State.Get(context.Background(), &state).HasError())
+			assert.Equal(t, "test-org||demo-provider||private", state.Id.ValueString())
+			assert.True(t, state.AdoptExisting.ValueBool())
+		})
+	}
+}
@@ -0,0 +1,396 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	m "github.com/tsanton/terraform-provider-tfepatch/provider/models"
+	api "github.com/tsanton/tfe-client/tfe"
+	apir "github.com/tsanton/tfe-client/tfe/models/request"
+	apis "github.com/tsanton/tfe-client/tfe/models/response"
+)
+
+type RegistryProviderVersionResource struct {
+	client       *api.TerraformEnterpriseClient
+	organization string
+	httpClient   *http.Client
+}
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &RegistryProviderVersionResource{}
+	_ resource.ResourceWithConfigure   = &RegistryProviderVersionResource{}
+	_ resource.ResourceWithImportState = &RegistryProviderVersionResource{}
+	_ resource.ResourceWithModifyPlan  = &RegistryProviderVersionResource{}
+)
+
+// newResource is a helper function to simplify the provider implementation.
+func newRegistryProviderVersionResource() resource.Resource {
+	return &RegistryProviderVersionResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *RegistryProviderVersionResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	data := req.ProviderData.(*providerData)
+	r.client = data.client
+	r.organization = data.organization
+	r.httpClient = data.httpClient
+}
+
+func (r *RegistryProviderVersionResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				Description:         "Unique id for this resource",
+				MarkdownDescription: "Unique id for this resource",
+			},
+			"shasums_uploaded": schema.BoolAttribute{
+				Computed:            true,
+				Description:         "Whether or not the SHA256SUMS file has been uploaded",
+				MarkdownDescription: "Whether or not the SHA256SUMS file has been uploaded",
+			},
+			"shasums_sig_uploaded": schema.BoolAttribute{
+				Computed:            true,
+				Description:         "Whether or not the SHA256SUMS.sig file has been uploaded",
+				MarkdownDescription: "Whether or not the SHA256SUMS.sig file has been uploaded",
+			},
+			"shasums_upload_url": schema.StringAttribute{
+				Computed:            true,
+				Description:         "The link the SHA256SUMS file is uploaded to. Only set while the file is yet to be uploaded",
+				MarkdownDescription: "The link the SHA256SUMS file is uploaded to. Only set while the file is yet to be uploaded",
+			},
+			"shasums_sig_upload_url": schema.StringAttribute{
+				Computed:            true,
+				Description:         "The link the SHA256SUMS.sig file is uploaded to. Only set while the file is yet to be uploaded",
+				MarkdownDescription: "The link the SHA256SUMS.sig file is uploaded to. Only set while the file is yet to be uploaded",
+			},
+			"shasums_download_url": schema.StringAttribute{
+				Computed:            true,
+				Description:         "The link the SHA256SUMS file can be downloaded from. Only set once the file has been uploaded",
+				MarkdownDescription: "The link the SHA256SUMS file can be downloaded from. Only set once the file has been uploaded",
+			},
+			"shasums_sig_download_url": schema.StringAttribute{
+				Computed:            true,
+				Description:         "The link the SHA256SUMS.sig file can be downloaded from. Only set once the file has been uploaded",
+				MarkdownDescription: "The link the SHA256SUMS.sig file can be downloaded from. Only set once the file has been uploaded",
+			},
+			// Input attributes
+			"organization": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Description:         "The organization name under which the provider registry exists. Defaults to the provider organization",
+				MarkdownDescription: "The organization name under which the provider registry exists. Defaults to the provider organization",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"namespace": schema.StringAttribute{
+				Required:            true,
+				Description:         "The namespace of the private provider registry. Must match the organization name",
+				MarkdownDescription: "The namespace of the private provider registry. Must match the organization name",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:            true,
+				Description:         "The name of the provider",
+				MarkdownDescription: "The name of the provider",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"version": schema.StringAttribute{
+				Required:            true,
+				Description:         "The semantic version of the provider version, i.e. '1.0.0'",
+				MarkdownDescription: "The semantic version of the provider version, i.e. `1.0.0`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"key_id": schema.StringAttribute{
+				Required:            true,
+				Description:         "The key_id of the GPG key (tfepatch_gpg_key) that the SHA256SUMS file is signed with",
+				MarkdownDescription: "The key_id of the GPG key (`tfepatch_gpg_key`) that the SHA256SUMS file is signed with",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"protocols": schema.ListAttribute{
+				Required:            true,
+				ElementType:         types.StringType,
+				Description:         "The Terraform plugin protocol versions the provider version supports, i.e. ['5.0'] or ['6.0']",
+				MarkdownDescription: "The Terraform plugin protocol versions the provider version supports, i.e. `[\"5.0\"]` or `[\"6.0\"]`",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ValueStringsAre(stringvalidator.OneOf("4.0", "5.0", "6.0")),
+				},
+			},
+			"shasums_path": schema.StringAttribute{
+				Required:            true,
+				Description:         "The local path to the SHA256SUMS file to upload",
+				MarkdownDescription: "The local path to the SHA256SUMS file to upload",
+				PlanModifiers: []planmodifier.String{
+					uploadPathRequiresReplace(),
+				},
+			},
+			"shasums_sig_path": schema.StringAttribute{
+				Required:            true,
+				Description:         "The local path to the SHA256SUMS.sig file to upload",
+				MarkdownDescription: "The local path to the SHA256SUMS.sig file to upload",
+				PlanModifiers: []planmodifier.String{
+					uploadPathRequiresReplace(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeoutsOpts),
+		},
+	}
+}
+
+// Metadata returns the resource type name.
+func (r *RegistryProviderVersionResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_registry_provider_version"
+}
+
+// ModifyPlan defaults the organization to the provider organization.
+func (r *RegistryProviderVersionResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	planOrganization(ctx, r.organization, req, resp)
+}
+
+func (r *RegistryProviderVersionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = recordApiErrors(ctx)
+	var plan m.RegistryProviderVersion
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	var protocols []string
+	diags = plan.Protocols.ElementsAs(ctx, &protocols, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	cr, err := r.client.ProviderVersionService.Create(ctx, plan.Organization.ValueString(), plan.Namespace.ValueString(), plan.Name.ValueString(), &apir.ProviderVersion{
+		Data: apir.ProviderVersionData{
+			Type: "registry-provider-versions",
+			Attributes: apir.ProviderVersionDataAttributes{
+				Version:   plan.Version.ValueString(),
+				KeyId:     plan.KeyId.ValueString(),
+				Protocols: protocols,
+			},
+		},
+	})
+	if err != nil {
+		addApiError(ctx, &resp.Diagnostics, "create the provider version", err, "version", "key_id", "protocols")
+		return
+	}
+
+	// The version is persisted before the uploads so that a failed upload leaves a tainted resource rather than an orphaned version
+	plan, diags = registryProviderVersionState(ctx, plan, cr.Data)
+	resp.Diagnostics.Append(diags...)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err = uploadFile(ctx, r.httpClient, cr.Data.Links.ShasumsUploadUrl, plan.ShasumsPath.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("shasums_path"),
+			"Error uploading SHA256SUMS",
+			"Could not upload SHA256SUMS "+err.Error(),
+		)
+		return
+	}
+
+	if err = uploadFile(ctx, r.httpClient, cr.Data.Links.ShasumsSigUploadUrl, plan.ShasumsSigPath.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("shasums_sig_path"),
+			"Error uploading SHA256SUMS.sig",
+			"Could not upload SHA256SUMS.sig "+err.Error(),
+		)
+		return
+	}
+
+	// Re-read the version to pick up the upload state and download links
+	rr, err := r.client.ProviderVersionService.Read(ctx, plan.Organization.ValueString(), plan.Namespace.ValueString(), plan.Name.ValueString(), plan.Version.ValueString())
+	if err != nil {
+		addApiError(ctx, &resp.Diagnostics, "read the provider version", err)
+		return
+	}
+
+	plan, diags = registryProviderVersionState(ctx, plan, rr.Data)
+	resp.Diagnostics.Append(diags...)
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (r *RegistryProviderVersionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = recordApiErrors(ctx)
+	var state m.RegistryProviderVersion
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	rr, err := r.client.ProviderVersionService.Read(ctx, state.Organization.ValueString(), state.Namespace.ValueString(), state.Name.ValueString(), state.Version.ValueString())
+	if isNotFound(err) {
+		// Deleted out of band: remove from state so that Terraform plans a recreate
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addApiError(ctx, &resp.Diagnostics, "read the provider version", err)
+		return
+	}
+
+	state, diags = registryProviderVersionState(ctx, state, rr.Data)
+	resp.Diagnostics.Append(diags...)
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update persists the upload paths of an imported version and the timeouts as all other attributes require replacement if changed
+func (r *RegistryProviderVersionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state m.RegistryProviderVersion
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.ShasumsPath = plan.ShasumsPath
+	state.ShasumsSigPath = plan.ShasumsSigPath
+	state.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *RegistryProviderVersionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = recordApiErrors(ctx)
+	var state m.RegistryProviderVersion
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	err := r.client.ProviderVersionService.Delete(ctx, state.Organization.ValueString(), state.Namespace.ValueString(), state.Name.ValueString(), state.Version.ValueString())
+	if err != nil {
+		addApiError(ctx, &resp.Diagnostics, "delete the provider version", err)
+		return
+	}
+
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *RegistryProviderVersionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts, ok := splitImportId(req.ID, "/", 4)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Invalid import id",
+			fmt.Sprintf("Expected import id on the format '<organization>/<namespace>/<name>/<version>', got: %s", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("organization"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("namespace"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), parts[2])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("version"), parts[3])...)
+}
+
+// uploadPathRequiresReplace requires replacement when a file to upload changes. An imported version has no paths in state,
+// so the configured paths are taken into state rather than replacing the published version.
+func uploadPathRequiresReplace() planmodifier.String {
+	return stringplanmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+			resp.RequiresReplace = !req.StateValue.IsNull()
+		},
+		"Changing the path requires replacement, except for imported versions as these have no path in state",
+		"Changing the path requires replacement, except for imported versions as these have no path in state",
+	)
+}
+
+// registryProviderVersionState maps the API response onto the resource model, keeping the local only attributes of the prior model.
+func registryProviderVersionState(ctx context.Context, prior m.RegistryProviderVersion, data apis.ProviderVersionData) (m.RegistryProviderVersion, diag.Diagnostics) {
+	protocols, diags := types.ListValueFrom(ctx, types.StringType, data.Attributes.Protocols)
+	return m.RegistryProviderVersion{
+		Id:                    types.StringValue(fmt.Sprintf("%s||%s||%s||%s", prior.Organization.ValueString(), prior.Namespace.ValueString(), prior.Name.ValueString(), data.Attributes.Version)),
+		Organization:          prior.Organization,
+		Namespace:             prior.Namespace,
+		Name:                  prior.Name,
+		Version:               types.StringValue(data.Attributes.Version),
+		KeyId:                 types.StringValue(data.Attributes.KeyId),
+		Protocols:             protocols,
+		ShasumsPath:           prior.ShasumsPath,
+		ShasumsSigPath:        prior.ShasumsSigPath,
+		ShasumsUploaded:       types.BoolValue(data.Attributes.ShasumsUploaded),
+		ShasumsSigUploaded:    types.BoolValue(data.Attributes.ShasumsSigUploaded),
+		ShasumsUploadUrl:      stringValueOrNull(data.Links.ShasumsUploadUrl),
+		ShasumsSigUploadUrl:   stringValueOrNull(data.Links.ShasumsSigUploadUrl),
+		ShasumsDownloadUrl:    stringValueOrNull(data.Links.ShasumsDownloadUrl),
+		ShasumsSigDownloadUrl: stringValueOrNull(data.Links.ShasumsSigDownloadUrl),
+		Timeouts:              prior.Timeouts,
+	}, diags
+}
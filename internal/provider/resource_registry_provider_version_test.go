@@ -0,0 +1,289 @@
+package provider_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	m "github.com/tsanton/terraform-provider-tfepatch/provider/models"
+	u "github.com/tsanton/terraform-provider-tfepatch/utilities"
+)
+
+func Test_provider_registry_provider_version(t *testing.T) {
+	useCassette(t)
+	/* Arrange */
+	t.Log("Arranging")
+	orgName := u.GetEnv("TFE_ORG_NAME", "")
+	namespace := orgName
+	name := "demo-provider"
+	version := "0.0.1"
+	entity, err := openpgp.NewEntity("Gruntwork", "Integration test GPG key", "donotreply@gruntwork.com", &packet.Config{RSABits: 4096})
+	if err != nil {
+		t.Log("Unable to generate GPG key entity")
+		t.FailNow()
+	}
+	publicKey, err := generateGpgKey(entity)
+	if err != nil {
+		t.Log("Unable to generate GPG key")
+		t.FailNow()
+	}
+	shasumsPath, shasumsSigPath, err := generateShasums(t.TempDir(), entity, fmt.Sprintf("terraform-provider-%s_%s", name, version))
+	if err != nil {
+		t.Log("Unable to generate SHA256SUMS")
+		t.FailNow()
+	}
+
+	/* Act */
+	log.Println("Invoking tests")
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			//--------------------------------------------------------------------------
+			//--- Create and Read testing
+			//--------------------------------------------------------------------------
+			{
+				Config: providerConfig + fmt.Sprintf(`
+				resource "tfepatch_registry_provider" "this" {
+					organization  = "%[1]s"
+					namespace     = "%[2]s"
+					name          = "%[3]s"
+					registry_name = "private"
+					force_destroy = true
+				  }
+
+				resource "tfepatch_gpg_key" "this" {
+					organization = "%[1]s"
+					namespace    = "%[2]s"
+					public_key   = trimspace(<<EOF
+%[4]s
+EOF
+)
+				  }
+
+				resource "tfepatch_registry_provider_version" "this" {
+					organization     = tfepatch_registry_provider.this.organization
+					namespace        = tfepatch_registry_provider.this.namespace
+					name             = tfepatch_registry_provider.this.name
+					version          = "%[5]s"
+					key_id           = tfepatch_gpg_key.this.key_id
+					protocols        = ["6.0"]
+					shasums_path     = "%[6]s"
+					shasums_sig_path = "%[7]s"
+				  }
+				`, orgName, namespace, name, publicKey, version, shasumsPath, shasumsSigPath),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_version.this", "organization", orgName),
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_version.this", "namespace", namespace),
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_version.this", "name", name),
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_version.this", "version", version),
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_version.this", "protocols.#", "1"),
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_version.this", "protocols.0", "6.0"),
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_version.this", "shasums_uploaded", "true"),
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_version.this", "shasums_sig_uploaded", "true"),
+					resource.TestCheckResourceAttrPair("tfepatch_registry_provider_version.this", "key_id", "tfepatch_gpg_key.this", "key_id"),
+				),
+			},
+			{
+				RefreshState: true,
+				PreConfig: func() {
+					providerVersion, err := cli.ProviderVersionService.Read(context.Background(), orgName, namespace, name, version)
+					assert.Nil(t, err)
+					assert.Equal(t, version, providerVersion.Data.Attributes.Version)
+					assert.True(t, providerVersion.Data.Attributes.ShasumsUploaded)
+					assert.True(t, providerVersion.Data.Attributes.ShasumsSigUploaded)
+				},
+			},
+		},
+	})
+}
+
+// generateShasums writes a SHA256SUMS file for the archives and a detached signature of it, signed by the entity, to dir.
+// Without archives a dummy linux_amd64 entry is written.
+func generateShasums(dir string, entity *openpgp.Entity, archivePrefix string, archivePaths ...string) (string, string, error) {
+	shasums := fmt.Sprintf("%x  %s_linux_amd64.zip\n", bytes.Repeat([]byte{0xab}, 32), archivePrefix)
+	if len(archivePaths) > 0 {
+		shasums = ""
+		for _, archivePath := range archivePaths {
+			content, err := os.ReadFile(archivePath)
+			if err != nil {
+				return "", "", err
+			}
+			shasums += fmt.Sprintf("%x  %s\n", sha256.Sum256(content), filepath.Base(archivePath))
+		}
+	}
+	shasumsPath := filepath.Join(dir, fmt.Sprintf("%s_SHA256SUMS", archivePrefix))
+	if err := os.WriteFile(shasumsPath, []byte(shasums), 0o600); err != nil {
+		return "", "", err
+	}
+
+	var signature bytes.Buffer
+	if err := openpgp.DetachSign(&signature, entity, bytes.NewBufferString(shasums), nil); err != nil {
+		return "", "", err
+	}
+	shasumsSigPath := shasumsPath + ".sig"
+	if err := os.WriteFile(shasumsSigPath, signature.Bytes(), 0o600); err != nil {
+		return "", "", err
+	}
+	return shasumsPath, shasumsSigPath, nil
+}
+
+func Test_registry_provider_version_lifecycle(t *testing.T) {
+	/* Arrange */
+	requireTerraformCli(t)
+	fake := newFakeTfeApi(t)
+	entity := newTestGpgEntity(t, time.Now(), 0)
+	shasumsPath, shasumsSigPath, err := generateShasums(t.TempDir(), entity, "terraform-provider-demo-provider_1.0.0")
+	assert.Nil(t, err)
+
+	/* Act */
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fake.providerConfig() + fmt.Sprintf(`
+				resource "tfepatch_registry_provider" "this" {
+					namespace     = "test-org"
+					name          = "demo-provider"
+					registry_name = "private"
+					force_destroy = true
+				  }
+
+				resource "tfepatch_gpg_key" "this" {
+					namespace  = "test-org"
+					public_key = trimspace(<<EOF
+%s
+EOF
+)
+				  }
+
+				resource "tfepatch_registry_provider_version" "this" {
+					namespace        = tfepatch_registry_provider.this.namespace
+					name             = tfepatch_registry_provider.this.name
+					version          = "1.0.0"
+					key_id           = tfepatch_gpg_key.this.key_id
+					protocols        = ["6.0"]
+					shasums_path     = "%s"
+					shasums_sig_path = "%s"
+				  }
+				`, armoredGpgKeys(t, openpgp.PublicKeyType, entity), shasumsPath, shasumsSigPath),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_version.this", "shasums_uploaded", "true"),
+					resource.TestCheckResourceAttr("tfepatch_registry_provider_version.this", "shasums_sig_uploaded", "true"),
+					resource.TestCheckResourceAttrPair("tfepatch_registry_provider_version.this", "key_id", "tfepatch_gpg_key.this", "key_id"),
+					func(_ *terraform.State) error {
+						provider := fake.registryProvider("private", fakeOrganization, "demo-provider")
+						shasums, _ := os.ReadFile(shasumsPath)
+						if provider == nil || len(provider.versions) != 1 || !bytes.Equal(provider.versions[0].shasums, shasums) {
+							return fmt.Errorf("the SHA256SUMS file of version 1.0.0 was not uploaded")
+						}
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:            "tfepatch_registry_provider_version.this",
+				ImportState:             true,
+				ImportStateId:           "test-org/test-org/demo-provider/1.0.0",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"shasums_path", "shasums_sig_path"},
+			},
+		},
+	})
+}
+
+func Test_registry_provider_version_import_id(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		err  string
+	}{
+		{name: "organization qualified", id: "test-org/test-org/demo-provider/1.0.0"},
+		{name: "legacy separator", id: "test-org||test-org||demo-provider||1.0.0", err: "Invalid import id"},
+		{name: "too few parts", id: "test-org/demo-provider/1.0.0", err: "Invalid import id"},
+		{name: "empty part", id: "test-org//demo-provider/1.0.0", err: "Invalid import id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* Arrange */
+			r := newTestResource(t, "tfepatch_registry_provider_version", testProviderValues("localhost"))
+
+			/* Act */
+			resp := importTestState(r, tt.id)
+
+			/* Assert */
+			if tt.err != "" {
+				assert.True(t, resp.Diagnostics.HasError())
+				assert.Equal(t, tt.err, resp.Diagnostics.Errors()[0].Summary())
+				return
+			}
+			assert.False(t, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+			var state m.RegistryProviderVersion
+			assert.False(t, resp.State.Get(context.Background(), &state).HasError())
+			assert.Equal(t, "test-org", state.Organization.ValueString())
+			assert.Equal(t, "test-org", state.Namespace.ValueString())
+			assert.Equal(t, "demo-provider", state.Name.ValueString())
+			assert.Equal(t, "1.0.0", state.Version.ValueString())
+		})
+	}
+}
+
+func Test_registry_provider_version_upload_paths_of_imported_version(t *testing.T) {
+	tests := []struct {
+		name    string
+		prior   types.String
+		replace bool
+	}{
+		{name: "imported version", prior: types.StringNull()},
+		{name: "changed path", prior: types.StringValue("/dist/SHA256SUMS"), replace: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* Arrange */
+			r := newTestResource(t, "tfepatch_registry_provider_version", testProviderValues("localhost"))
+			var schemaResp fwresource.SchemaResponse
+			r.Schema(context.Background(), fwresource.SchemaRequest{}, &schemaResp)
+			state := newTestState(t, r, &m.RegistryProviderVersion{
+				Organization: types.StringValue("test-org"),
+				Namespace:    types.StringValue("test-org"),
+				Name:         types.StringValue("demo-provider"),
+				Version:      types.StringValue("1.0.0"),
+				Protocols:    types.ListNull(types.StringType),
+				ShasumsPath:  tt.prior,
+			})
+			req := planmodifier.StringRequest{
+				Path:       path.Root("shasums_path"),
+				State:      state,
+				Plan:       tfsdk.Plan{Schema: state.Schema, Raw: state.Raw},
+				StateValue: tt.prior,
+				PlanValue:  types.StringValue("/release/SHA256SUMS"),
+			}
+			resp := &planmodifier.StringResponse{PlanValue: req.PlanValue}
+
+			/* Act */
+			for _, modifier := range schemaResp.Schema.Attributes["shasums_path"].(schema.StringAttribute).PlanModifiers {
+				modifier.PlanModifyString(context.Background(), req, resp)
+			}
+
+			/* Assert */
+			assert.False(t, resp.Diagnostics.HasError())
+			assert.Equal(t, tt.replace, resp.RequiresReplace)
+		})
+	}
+}
@@ -0,0 +1,345 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	m "github.com/tsanton/terraform-provider-tfepatch/provider/models"
+)
+
+type ShasumsSignatureResource struct{}
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                     = &ShasumsSignatureResource{}
+	_ resource.ResourceWithModifyPlan       = &ShasumsSignatureResource{}
+	_ resource.ResourceWithConfigValidators = &ShasumsSignatureResource{}
+)
+
+// newResource is a helper function to simplify the provider implementation.
+func newShasumsSignatureResource() resource.Resource {
+	return &ShasumsSignatureResource{}
+}
+
+func (r *ShasumsSignatureResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description:         "Signs a SHA256SUMS file with a GPG private key, producing the detached binary signature a provider version is published with",
+		MarkdownDescription: "Signs a `SHA256SUMS` file with a GPG private key, producing the detached binary signature a provider version is published with",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				Description:         "Unique id for this resource",
+				MarkdownDescription: "Unique id for this resource",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"shasums_sha256": schema.StringAttribute{
+				Computed:            true,
+				Description:         "The sha256 checksum of the signed SHA256SUMS content. Computed at plan time, a change forces a new signature",
+				MarkdownDescription: "The sha256 checksum of the signed `SHA256SUMS` content. Computed at plan time, a change forces a new signature",
+			},
+			"signature": schema.StringAttribute{
+				Computed:            true,
+				Description:         "The base64 encoded detached binary signature of the SHA256SUMS content",
+				MarkdownDescription: "The base64 encoded detached binary signature of the `SHA256SUMS` content",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			// Input attributes
+			"key_id": schema.StringAttribute{
+				Required:            true,
+				Description:         "The key_id of the tfepatch_gpg_key the private key must be the private key of",
+				MarkdownDescription: "The `key_id` of the `tfepatch_gpg_key` the private key must be the private key of",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"private_key": schema.StringAttribute{
+				Required:            true,
+				Sensitive:           true,
+				Description:         "The ASCII-armored GPG private key to sign with",
+				MarkdownDescription: "The ASCII-armored GPG private key to sign with",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"passphrase": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           true,
+				Description:         "The passphrase of the private key, when it is encrypted",
+				MarkdownDescription: "The passphrase of the private key, when it is encrypted",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"shasums": schema.StringAttribute{
+				Optional:            true,
+				Description:         "The SHA256SUMS content to sign. Conflicts with shasums_path",
+				MarkdownDescription: "The `SHA256SUMS` content to sign. Conflicts with `shasums_path`",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"shasums_path": schema.StringAttribute{
+				Optional:            true,
+				Description:         "The local path to the SHA256SUMS file to sign. Conflicts with shasums",
+				MarkdownDescription: "The local path to the `SHA256SUMS` file to sign. Conflicts with `shasums`",
+			},
+			"signature_path": schema.StringAttribute{
+				Optional:            true,
+				Description:         "The local path to write the binary signature to, i.e. the shasums_sig_path of a tfepatch_registry_provider_version. The file is deleted with the resource",
+				MarkdownDescription: "The local path to write the binary signature to, i.e. the `shasums_sig_path` of a `tfepatch_registry_provider_version`. The file is deleted with the resource",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (r *ShasumsSignatureResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("shasums"),
+			path.MatchRoot("shasums_path"),
+		),
+	}
+}
+
+// Metadata returns the resource type name.
+func (r *ShasumsSignatureResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_shasums_signature"
+}
+
+// ModifyPlan checks that the private key is the key of key_id and computes the checksum of the SHA256SUMS content,
+// so that a change in content forces a new signature.
+func (r *ShasumsSignatureResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to compute on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan m.ShasumsSignature
+	diags := resp.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.PrivateKey.IsUnknown() && !plan.Passphrase.IsUnknown() && !plan.KeyId.IsUnknown() {
+		_, diags = signingKey(plan)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// The content may be produced by another resource, in which case the checksum is known after apply
+	if plan.Shasums.IsUnknown() || plan.ShasumsPath.IsUnknown() {
+		return
+	}
+	content, diags := shasumsContent(plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.ShasumsSha256 = types.StringValue(contentSha256(content))
+
+	if !req.State.Raw.IsNull() {
+		var state m.ShasumsSignature
+		diags = req.State.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if state.ShasumsSha256.ValueString() != plan.ShasumsSha256.ValueString() {
+			resp.RequiresReplace = append(resp.RequiresReplace, path.Root("shasums_sha256"))
+			plan.Id = types.StringUnknown()
+			plan.Signature = types.StringUnknown()
+		}
+	}
+
+	diags = resp.Plan.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *ShasumsSignatureResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan m.ShasumsSignature
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The key and content may have been unknown during plan
+	entity, diags := signingKey(plan)
+	resp.Diagnostics.Append(diags...)
+	content, diags := shasumsContent(plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	shasumsSha256 := contentSha256(content)
+	if !plan.ShasumsSha256.IsUnknown() && plan.ShasumsSha256.ValueString() != shasumsSha256 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("shasums_path"),
+			"SHA256SUMS changed after plan",
+			fmt.Sprintf("The planned checksum %s does not match the checksum %s of the SHA256SUMS content. Re-run the plan", plan.ShasumsSha256.ValueString(), shasumsSha256),
+		)
+		return
+	}
+
+	var signature bytes.Buffer
+	if err := openpgp.DetachSign(&signature, entity, bytes.NewReader(content), nil); err != nil {
+		resp.Diagnostics.AddError(
+			"Error signing SHA256SUMS",
+			"Could not sign the SHA256SUMS content "+err.Error(),
+		)
+		return
+	}
+	if !plan.SignaturePath.IsNull() {
+		if err := os.WriteFile(plan.SignaturePath.ValueString(), signature.Bytes(), 0o644); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("signature_path"),
+				"Error writing signature",
+				"Could not write the SHA256SUMS signature "+err.Error(),
+			)
+			return
+		}
+	}
+
+	plan.Id = types.StringValue(fmt.Sprintf("%s||%s", plan.KeyId.ValueString(), shasumsSha256))
+	plan.ShasumsSha256 = types.StringValue(shasumsSha256)
+	plan.Signature = types.StringValue(base64.StdEncoding.EncodeToString(signature.Bytes()))
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read removes the resource when the signature file is gone, so that Terraform plans to sign again
+func (r *ShasumsSignatureResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state m.ShasumsSignature
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || state.SignaturePath.IsNull() {
+		return
+	}
+
+	written, err := os.ReadFile(state.SignaturePath.ValueString())
+	if errors.Is(err, os.ErrNotExist) || (err == nil && base64.StdEncoding.EncodeToString(written) != state.Signature.ValueString()) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("signature_path"),
+			"Error reading signature",
+			"Could not read the SHA256SUMS signature "+err.Error(),
+		)
+	}
+}
+
+// Update only persists a moved shasums_path with unchanged content as every other change requires replacement
+func (r *ShasumsSignatureResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state m.ShasumsSignature
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.ShasumsPath = plan.ShasumsPath
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the signature file
+func (r *ShasumsSignatureResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state m.ShasumsSignature
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !state.SignaturePath.IsNull() {
+		if err := os.Remove(state.SignaturePath.ValueString()); err != nil && !errors.Is(err, os.ErrNotExist) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("signature_path"),
+				"Error deleting signature",
+				"Could not delete the SHA256SUMS signature "+err.Error(),
+			)
+			return
+		}
+	}
+	resp.State.RemoveResource(ctx)
+}
+
+// signingKey reads the private key of the signature, which must be the key of key_id.
+func signingKey(signature m.ShasumsSignature) (*openpgp.Entity, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	entity, err := readSigningKey(signature.PrivateKey.ValueString(), signature.Passphrase.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("private_key"),
+			"Invalid GPG private key",
+			"The private key cannot sign: "+err.Error(),
+		)
+		return nil, diags
+	}
+	if keyId := gpgKeyId(entity); !strings.EqualFold(keyId, signature.KeyId.ValueString()) {
+		diags.AddAttributeError(
+			path.Root("key_id"),
+			"GPG key mismatch",
+			fmt.Sprintf("The private key is the key of key_id %s, not of %s. Signatures must be made with the key the provider version is registered with.", keyId, signature.KeyId.ValueString()),
+		)
+		return nil, diags
+	}
+	return entity, diags
+}
+
+// shasumsContent returns the SHA256SUMS content of the signature, from shasums or the file at shasums_path.
+func shasumsContent(signature m.ShasumsSignature) ([]byte, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if !signature.Shasums.IsNull() {
+		return []byte(signature.Shasums.ValueString()), diags
+	}
+	content, err := os.ReadFile(signature.ShasumsPath.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("shasums_path"),
+			"Unable to read SHA256SUMS",
+			"Could not read the SHA256SUMS file "+err.Error(),
+		)
+	}
+	return content, diags
+}
+
+func contentSha256(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,127 @@
+package provider_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	m "github.com/tsanton/terraform-provider-tfepatch/provider/models"
+)
+
+const testShasums = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef  terraform-provider-demo_1.0.0_linux_amd64.zip\n"
+
+// testKeyId returns the key_id TFE registers the primary key of the entity under.
+func testKeyId(entity *openpgp.Entity) string {
+	return entity.PrimaryKey.KeyIdString()
+}
+
+func Test_shasums_signature_signs_shasums(t *testing.T) {
+	/* Arrange */
+	entity := loadCassetteGpgEntity(t)
+	dir := t.TempDir()
+	shasumsPath := filepath.Join(dir, "SHA256SUMS")
+	signaturePath := filepath.Join(dir, "SHA256SUMS.sig")
+	assert.Nil(t, os.WriteFile(shasumsPath, []byte(testShasums), 0o644))
+	r := newTestResource(t, "tfepatch_shasums_signature", testProviderValues("localhost"))
+	req := newTestModifyPlanRequest(t, r, map[string]tftypes.Value{
+		"key_id":         tftypes.NewValue(tftypes.String, testKeyId(entity)),
+		"private_key":    tftypes.NewValue(tftypes.String, armoredGpgKeys(t, openpgp.PrivateKeyType, entity)),
+		"shasums_path":   tftypes.NewValue(tftypes.String, shasumsPath),
+		"signature_path": tftypes.NewValue(tftypes.String, signaturePath),
+	}, nil)
+	planResp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+	r.(fwresource.ResourceWithModifyPlan).ModifyPlan(context.Background(), req, &planResp)
+	assert.False(t, planResp.Diagnostics.HasError(), "%v", planResp.Diagnostics)
+	createResp := fwresource.CreateResponse{State: tfsdk.State{Schema: req.State.Schema, Raw: req.State.Raw}}
+
+	/* Act */
+	r.Create(context.Background(), fwresource.CreateRequest{Config: req.Config, Plan: planResp.Plan}, &createResp)
+
+	/* Assert */
+	assert.False(t, createResp.Diagnostics.HasError(), "%v", createResp.Diagnostics)
+	var state m.ShasumsSignature
+	assert.False(t, createResp.State.Get(context.Background(), &state).HasError())
+	signature, err := base64.StdEncoding.DecodeString(state.Signature.ValueString())
+	assert.Nil(t, err)
+	signer, err := openpgp.CheckDetachedSignature(openpgp.EntityList{entity}, bytes.NewReader([]byte(testShasums)), bytes.NewReader(signature), nil)
+	assert.Nil(t, err)
+	if signer != nil {
+		assert.Equal(t, entity.PrimaryKey.KeyId, signer.PrimaryKey.KeyId)
+	}
+	written, err := os.ReadFile(signaturePath)
+	assert.Nil(t, err)
+	assert.Equal(t, signature, written)
+}
+
+func Test_shasums_signature_requires_the_registered_key(t *testing.T) {
+	/* Arrange */
+	entity := loadCassetteGpgEntity(t)
+	r := newTestResource(t, "tfepatch_shasums_signature", testProviderValues("localhost"))
+	req := newTestModifyPlanRequest(t, r, map[string]tftypes.Value{
+		"key_id":      tftypes.NewValue(tftypes.String, "0123456789ABCDEF"),
+		"private_key": tftypes.NewValue(tftypes.String, armoredGpgKeys(t, openpgp.PrivateKeyType, entity)),
+		"shasums":     tftypes.NewValue(tftypes.String, testShasums),
+	}, nil)
+	resp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+
+	/* Act */
+	r.(fwresource.ResourceWithModifyPlan).ModifyPlan(context.Background(), req, &resp)
+
+	/* Assert */
+	assert.Equal(t, 1, resp.Diagnostics.ErrorsCount())
+	assert.Equal(t, "GPG key mismatch", resp.Diagnostics.Errors()[0].Summary())
+	withPath, ok := resp.Diagnostics.Errors()[0].(interface{ Path() path.Path })
+	assert.True(t, ok)
+	if ok {
+		assert.Equal(t, path.Root("key_id"), withPath.Path())
+	}
+}
+
+func Test_shasums_signature_decrypts_the_private_key(t *testing.T) {
+	armored, err := os.ReadFile("testdata/gpg_test_key_encrypted.asc")
+	assert.Nil(t, err)
+	entities, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(armored))
+	assert.Nil(t, err)
+	tests := []struct {
+		name       string
+		passphrase tftypes.Value
+		summary    string
+	}{
+		{name: "with passphrase", passphrase: tftypes.NewValue(tftypes.String, "test-passphrase")},
+		{name: "without passphrase", passphrase: tftypes.NewValue(tftypes.String, nil), summary: "Invalid GPG private key"},
+		{name: "with incorrect passphrase", passphrase: tftypes.NewValue(tftypes.String, "incorrect"), summary: "Invalid GPG private key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* Arrange */
+			r := newTestResource(t, "tfepatch_shasums_signature", testProviderValues("localhost"))
+			req := newTestModifyPlanRequest(t, r, map[string]tftypes.Value{
+				"key_id":      tftypes.NewValue(tftypes.String, testKeyId(entities[0])),
+				"private_key": tftypes.NewValue(tftypes.String, string(armored)),
+				"passphrase":  tt.passphrase,
+				"shasums":     tftypes.NewValue(tftypes.String, testShasums),
+			}, nil)
+			resp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+
+			/* Act */
+			r.(fwresource.ResourceWithModifyPlan).ModifyPlan(context.Background(), req, &resp)
+
+			/* Assert */
+			if tt.summary == "" {
+				assert.False(t, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+				return
+			}
+			assert.Equal(t, 1, resp.Diagnostics.ErrorsCount())
+			assert.Equal(t, tt.summary, resp.Diagnostics.Errors()[0].Summary())
+		})
+	}
+}
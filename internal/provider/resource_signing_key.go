@@ -0,0 +1,417 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	m "github.com/tsanton/terraform-provider-tfepatch/provider/models"
+	api "github.com/tsanton/tfe-client/tfe"
+	apir "github.com/tsanton/tfe-client/tfe/models/request"
+)
+
+const (
+	// signingKeyBits is the RSA modulus length of generated signing keys
+	signingKeyBits = 4096
+	// signingKeyPassphraseBytes is the entropy of generated passphrases, 32 characters once encoded
+	signingKeyPassphraseBytes = 24
+	// signingKeyMaxExpiryDays is the longest lifetime the 32-bit key lifetime in seconds can hold
+	signingKeyMaxExpiryDays = math.MaxUint32 / (24 * 60 * 60)
+)
+
+type SigningKeyResource struct {
+	client       *api.TerraformEnterpriseClient
+	organization string
+}
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource               = &SigningKeyResource{}
+	_ resource.ResourceWithConfigure  = &SigningKeyResource{}
+	_ resource.ResourceWithModifyPlan = &SigningKeyResource{}
+)
+
+// userIdCharacters rejects the characters delimiting the comment and email of an OpenPGP user id
+var userIdCharacters = stringvalidator.RegexMatches(regexp.MustCompile(`^[^()<>\x00]*$`), "must not contain any of ()<>")
+
+// newResource is a helper function to simplify the provider implementation.
+func newSigningKeyResource() resource.Resource {
+	return &SigningKeyResource{}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *SigningKeyResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	data := req.ProviderData.(*providerData)
+	r.client = data.client
+	r.organization = data.organization
+}
+
+func (r *SigningKeyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description:         "Generates an RSA-4096 GPG signing key with a generated passphrase and registers its public key. The private key and passphrase are kept in state",
+		MarkdownDescription: "Generates an RSA-4096 GPG signing key with a generated passphrase and registers its public key. The private key and passphrase are kept in state",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				Description:         "Unique id for this resource",
+				MarkdownDescription: "Unique id for this resource",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"key_id": schema.StringAttribute{
+				Computed:            true,
+				Description:         "The identity of the registered key",
+				MarkdownDescription: "The identity of the registered key",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"fingerprint": schema.StringAttribute{
+				Computed:            true,
+				Description:         "The fingerprint of the primary key",
+				MarkdownDescription: "The fingerprint of the primary key",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"public_key": schema.StringAttribute{
+				Computed:            true,
+				Description:         "The ASCII-armored public key",
+				MarkdownDescription: "The ASCII-armored public key",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"private_key": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				Description:         "The ASCII-armored private key, encrypted with the passphrase",
+				MarkdownDescription: "The ASCII-armored private key, encrypted with the `passphrase`",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"passphrase": schema.StringAttribute{
+				Computed:            true,
+				Sensitive:           true,
+				Description:         "The generated passphrase of the private key",
+				MarkdownDescription: "The generated passphrase of the private key",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"key_created_at": schema.StringAttribute{
+				Computed:            true,
+				Description:         "The RFC3339 timestamp of when the key was created",
+				MarkdownDescription: "The RFC3339 timestamp of when the key was created",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"key_expires_at": schema.StringAttribute{
+				Computed:            true,
+				Description:         "The RFC3339 timestamp of when the key expires. Null for keys that do not expire",
+				MarkdownDescription: "The RFC3339 timestamp of when the key expires. Null for keys that do not expire",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			// Input attributes
+			"organization": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Description:         "The organization name under which this GPG key will exist. Defaults to the provider organization",
+				MarkdownDescription: "The organization name under which this GPG key will exist. Defaults to the provider organization",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"namespace": schema.StringAttribute{
+				Required:            true,
+				Description:         "The provider, by namespace, that this GPG key is affiliated with.",
+				MarkdownDescription: "The provider, by namespace, that this GPG key is affiliated with.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:            true,
+				Description:         "The name of the key identity",
+				MarkdownDescription: "The name of the key identity",
+				Validators: []validator.String{
+					userIdCharacters,
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"email": schema.StringAttribute{
+				Required:            true,
+				Description:         "The email of the key identity",
+				MarkdownDescription: "The email of the key identity",
+				Validators: []validator.String{
+					userIdCharacters,
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"comment": schema.StringAttribute{
+				Optional:            true,
+				Description:         "The comment of the key identity",
+				MarkdownDescription: "The comment of the key identity",
+				Validators: []validator.String{
+					userIdCharacters,
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"expiry_days": schema.Int64Attribute{
+				Optional:            true,
+				Description:         "The number of days the key is valid for, at most 49710. The key does not expire when unset",
+				MarkdownDescription: "The number of days the key is valid for, at most `49710`. The key does not expire when unset",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+					// The key lifetime is a 32-bit count of seconds
+					int64validator.AtMost(signingKeyMaxExpiryDays),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeoutsOpts),
+		},
+	}
+}
+
+// Metadata returns the resource type name.
+func (r *SigningKeyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_signing_key"
+}
+
+// ModifyPlan defaults the organization to the provider organization.
+func (r *SigningKeyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	planOrganization(ctx, r.organization, req, resp)
+}
+
+func (r *SigningKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = recordApiErrors(ctx)
+	var plan m.SigningKey
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	var metadata gpgKeyMetadata
+	publicKey, privateKey, passphrase, err := generateSigningKey(plan.Name.ValueString(), plan.Comment.ValueString(), plan.Email.ValueString(), plan.ExpiryDays.ValueInt64())
+	if err == nil {
+		metadata, err = parsePublicKey(publicKey)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error generating signing key",
+			"Could not generate the GPG signing key "+err.Error(),
+		)
+		return
+	}
+
+	cr, err := r.client.GpgService.Create(ctx, &apir.Gpg{
+		Data: apir.GpgData{
+			Type: "gpg-keys",
+			Attributes: apir.GpgDataAttributes{
+				AsciiArmor: publicKey,
+				Namespace:  plan.Namespace.ValueString(),
+			},
+		},
+	})
+	if err != nil {
+		addApiError(ctx, &resp.Diagnostics, "register the signing key", err, "namespace")
+		return
+	}
+
+	plan.Id = types.StringValue(fmt.Sprintf(strings.ToLower("%s||%s"), cr.Data.Attributes.Namespace, cr.Data.Attributes.KeyId))
+	plan.KeyId = types.StringValue(cr.Data.Attributes.KeyId)
+	plan.PublicKey = types.StringValue(publicKey)
+	plan.PrivateKey = types.StringValue(privateKey)
+	plan.Passphrase = types.StringValue(passphrase)
+	plan.Fingerprint = types.StringValue(metadata.fingerprint)
+	plan.KeyCreatedAt = types.StringValue(metadata.createdAt.Format(time.RFC3339))
+	plan.KeyExpiresAt = types.StringNull()
+	if metadata.expiresAt != nil {
+		plan.KeyExpiresAt = types.StringValue(metadata.expiresAt.Format(time.RFC3339))
+	}
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read removes the key from state when it is no longer registered, as the private key can only be generated anew
+func (r *SigningKeyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = recordApiErrors(ctx)
+	var state m.SigningKey
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, defaultReadTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	rr, err := r.client.GpgService.Read(ctx, state.Namespace.ValueString(), state.KeyId.ValueString())
+	if isNotFound(err) {
+		// Deleted out of band: remove from state so that Terraform plans a recreate
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		addApiError(ctx, &resp.Diagnostics, "read the signing key", err)
+		return
+	}
+	// A key registered anew under the same id is another key, which the private key in state does not belong to
+	if !sameGpgKey(state.PublicKey.ValueString(), rr.Data.Attributes.AsciiArmor) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.Id = types.StringValue(fmt.Sprintf(strings.ToLower("%s||%s"), rr.Data.Attributes.Namespace, rr.Data.Attributes.KeyId))
+	state.Namespace = types.StringValue(rr.Data.Attributes.Namespace)
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update is never called as every input attribute requires replacement
+func (r *SigningKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan m.SigningKey
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *SigningKeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = recordApiErrors(ctx)
+	var state m.SigningKey
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, defaultDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	err := r.client.GpgService.Delete(ctx, state.Namespace.ValueString(), state.KeyId.ValueString())
+	if err != nil && !isNotFound(err) {
+		addApiError(ctx, &resp.Diagnostics, "delete the signing key", err)
+		return
+	}
+	resp.State.RemoveResource(ctx)
+}
+
+// generateSigningKey generates an RSA key with a signing primary key, expiring after expiryDays unless zero, and returns its armored halves
+// with the generated passphrase the private key is encrypted with.
+func generateSigningKey(name, comment, email string, expiryDays int64) (publicKey, privateKey, passphrase string, err error) {
+	config := &packet.Config{
+		RSABits:         signingKeyBits,
+		DefaultHash:     crypto.SHA256,
+		KeyLifetimeSecs: uint32(expiryDays * 24 * 60 * 60),
+	}
+	entity, err := openpgp.NewEntity(name, comment, email, config)
+	if err != nil {
+		return "", "", "", err
+	}
+	publicKey, err = armoredEntity(openpgp.PublicKeyType, entity.Serialize)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	secret := make([]byte, signingKeyPassphraseBytes)
+	if _, err = rand.Read(secret); err != nil {
+		return "", "", "", err
+	}
+	passphrase = base64.RawURLEncoding.EncodeToString(secret)
+	if err = entity.PrivateKey.Encrypt([]byte(passphrase)); err != nil {
+		return "", "", "", err
+	}
+	for _, subkey := range entity.Subkeys {
+		if err = subkey.PrivateKey.Encrypt([]byte(passphrase)); err != nil {
+			return "", "", "", err
+		}
+	}
+	// The self-signatures were made on generation and cannot be made anew with the encrypted key
+	privateKey, err = armoredEntity(openpgp.PrivateKeyType, func(w io.Writer) error {
+		return entity.SerializePrivateWithoutSigning(w, nil)
+	})
+	if err != nil {
+		return "", "", "", err
+	}
+	return publicKey, privateKey, passphrase, nil
+}
+
+// armoredEntity armors the output of serialize in a block of the block type.
+func armoredEntity(blockType string, serialize func(io.Writer) error) (string, error) {
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, blockType, nil)
+	if err != nil {
+		return "", err
+	}
+	if err = serialize(w); err != nil {
+		return "", err
+	}
+	if err = w.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,121 @@
+package provider_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	m "github.com/tsanton/terraform-provider-tfepatch/provider/models"
+)
+
+func Test_signing_key_generates_and_registers_key(t *testing.T) {
+	/* Arrange */
+	fake := newFakeTfeApi(t)
+	providerValues := testProviderValues(fake.server.URL)
+	providerValues["token"] = tftypes.NewValue(tftypes.String, fakeToken)
+	r := newTestResource(t, "tfepatch_signing_key", providerValues)
+	req := newTestModifyPlanRequest(t, r, map[string]tftypes.Value{
+		"namespace":   tftypes.NewValue(tftypes.String, fakeOrganization),
+		"name":        tftypes.NewValue(tftypes.String, "Gruntwork"),
+		"email":       tftypes.NewValue(tftypes.String, "donotreply@gruntwork.com"),
+		"expiry_days": tftypes.NewValue(tftypes.Number, 365),
+	}, nil)
+	planResp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+	r.(fwresource.ResourceWithModifyPlan).ModifyPlan(context.Background(), req, &planResp)
+	createResp := fwresource.CreateResponse{State: tfsdk.State{Schema: req.State.Schema, Raw: req.State.Raw}}
+
+	/* Act */
+	r.Create(context.Background(), fwresource.CreateRequest{Config: req.Config, Plan: planResp.Plan}, &createResp)
+
+	/* Assert */
+	assert.False(t, createResp.Diagnostics.HasError(), "%v", createResp.Diagnostics)
+	var state m.SigningKey
+	assert.False(t, createResp.State.Get(context.Background(), &state).HasError())
+	assert.Equal(t, fakeOrganization, state.Organization.ValueString())
+	assert.NotNil(t, fake.gpgKey(fakeOrganization, state.KeyId.ValueString()))
+	assert.Equal(t, fakeOrganization+"||"+state.KeyId.ValueString(), state.Id.ValueString())
+	assert.False(t, state.KeyExpiresAt.IsNull())
+
+	entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(state.PrivateKey.ValueString()))
+	assert.Nil(t, err)
+	if assert.Len(t, entities, 1) {
+		entity := entities[0]
+		assert.Equal(t, state.KeyId.ValueString(), entity.PrimaryKey.KeyIdString())
+		assert.Contains(t, entity.Identities, "Gruntwork <donotreply@gruntwork.com>")
+		assert.True(t, entity.PrivateKey.Encrypted)
+		assert.NotNil(t, entity.PrivateKey.Decrypt([]byte("incorrect")))
+		assert.Nil(t, entity.PrivateKey.Decrypt([]byte(state.Passphrase.ValueString())))
+
+		var signature bytes.Buffer
+		assert.Nil(t, openpgp.DetachSign(&signature, entity, strings.NewReader(testShasums), nil))
+		public, err := openpgp.ReadArmoredKeyRing(strings.NewReader(state.PublicKey.ValueString()))
+		assert.Nil(t, err)
+		_, err = openpgp.CheckDetachedSignature(public, strings.NewReader(testShasums), &signature, nil)
+		assert.Nil(t, err)
+	}
+}
+
+func Test_signing_key_deleted_out_of_band(t *testing.T) {
+	/* Arrange */
+	server := newTestApiServer(t, notFoundHandler)
+	r := newTestResource(t, "tfepatch_signing_key", testProviderValues(server.URL))
+	state := newTestState(t, r, &m.SigningKey{
+		Id:           types.StringValue("test-org||0123456789abcdef"),
+		Organization: types.StringValue("test-org"),
+		Namespace:    types.StringValue("test-org"),
+		Name:         types.StringValue("Gruntwork"),
+		Email:        types.StringValue("donotreply@gruntwork.com"),
+		KeyId:        types.StringValue("0123456789ABCDEF"),
+	})
+	resp := fwresource.ReadResponse{State: state}
+
+	/* Act */
+	r.Read(context.Background(), fwresource.ReadRequest{State: state}, &resp)
+
+	/* Assert */
+	assert.False(t, resp.Diagnostics.HasError())
+	assert.True(t, resp.State.Raw.IsNull())
+}
+
+func Test_signing_key_expiry_days_fit_the_key_lifetime(t *testing.T) {
+	tests := []struct {
+		name  string
+		days  int64
+		valid bool
+	}{
+		{name: "a year", days: 365, valid: true},
+		{name: "longest lifetime", days: 49710, valid: true},
+		{name: "overflowing lifetime", days: 49711},
+		{name: "zero", days: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* Arrange */
+			r := newTestResource(t, "tfepatch_signing_key", testProviderValues("localhost"))
+			var schemaResp fwresource.SchemaResponse
+			r.Schema(context.Background(), fwresource.SchemaRequest{}, &schemaResp)
+			resp := &validator.Int64Response{}
+
+			/* Act */
+			for _, v := range schemaResp.Schema.Attributes["expiry_days"].(schema.Int64Attribute).Validators {
+				v.ValidateInt64(context.Background(), validator.Int64Request{
+					Path:        path.Root("expiry_days"),
+					ConfigValue: types.Int64Value(tt.days),
+				}, resp)
+			}
+
+			/* Assert */
+			assert.Equal(t, !tt.valid, resp.Diagnostics.HasError())
+		})
+	}
+}
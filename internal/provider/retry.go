@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	defaultMaxRetries   = 5
+	defaultRetryWaitMin = 1 * time.Second
+	defaultRetryWaitMax = 30 * time.Second
+)
+
+// retryTransport retries throttled and transiently failing requests with exponential backoff.
+// Throttled requests are retried for every method as TFE rejected them without processing,
+// server errors and connection failures only for idempotent methods so that a create is never sent twice.
+type retryTransport struct {
+	next       http.RoundTripper
+	maxRetries int
+	waitMin    time.Duration
+	waitMax    time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	attemptReq := req
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(attemptReq)
+		if attempt >= t.maxRetries || !retryable(req, resp, err) {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt, resp)
+		// Waiting past the deadline of the request only to fail on it would hide the response that made it wait
+		if deadline, ok := req.Context().Deadline(); ok && time.Now().Add(wait).After(deadline) {
+			return resp, err
+		}
+		fields := map[string]interface{}{"method": req.Method, "path": req.URL.Path, "attempt": attempt + 1, "wait": wait.String()}
+		if resp != nil {
+			fields["status"] = resp.StatusCode
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		} else {
+			fields["error"] = err.Error()
+		}
+		tflog.Debug(req.Context(), "Retrying TFE API request", fields)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		attemptReq = req.Clone(req.Context())
+		if req.GetBody != nil {
+			if attemptReq.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// retryable reports whether the outcome of the request warrants another attempt.
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	// A streamed body, i.e. an upload from file, cannot be sent again
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	if err != nil {
+		return req.Context().Err() == nil && idempotent(req.Method)
+	}
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return true
+	case resp.StatusCode >= http.StatusInternalServerError && resp.StatusCode != http.StatusNotImplemented:
+		return idempotent(req.Method)
+	}
+	return false
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// backoff returns the wait before the next attempt: the wait the server asks for, else waitMin doubled per attempt, both up to waitMax.
+func (t *retryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := serverWait(resp.Header); ok {
+			if wait > t.waitMax {
+				return t.waitMax
+			}
+			return wait
+		}
+	}
+	wait := t.waitMin << attempt
+	if wait > t.waitMax || wait < t.waitMin {
+		return t.waitMax
+	}
+	return wait
+}
+
+// serverWait reads the wait from the Retry-After header, in seconds or as a date, or the X-RateLimit-Reset header TFE sends in (fractional) seconds.
+func serverWait(header http.Header) (time.Duration, bool) {
+	if value := header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if date, err := http.ParseTime(value); err == nil {
+			if wait := time.Until(date); wait > 0 {
+				return wait, true
+			}
+			return 0, true
+		}
+	}
+	if value := header.Get("X-RateLimit-Reset"); value != "" {
+		if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds >= 0 {
+			return time.Duration(seconds * float64(time.Second)), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,196 @@
+package provider_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+
+	m "github.com/tsanton/terraform-provider-tfepatch/provider/models"
+)
+
+func Test_provider_retries_throttled_requests(t *testing.T) {
+	/* Arrange */
+	isolateCliConfig(t)
+	server, requests := newThrottlingApiServer(t, 2, func(w http.ResponseWriter) {
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	/* Act & Assert */
+	assertAuthenticated(t, retryProviderValues(server.URL, 5))
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func Test_provider_retries_server_errors(t *testing.T) {
+	/* Arrange */
+	isolateCliConfig(t)
+	server, requests := newThrottlingApiServer(t, 1, func(w http.ResponseWriter) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	/* Act & Assert */
+	assertAuthenticated(t, retryProviderValues(server.URL, 5))
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func Test_provider_retries_exhausted(t *testing.T) {
+	/* Arrange */
+	isolateCliConfig(t)
+	server, requests := newThrottlingApiServer(t, 10, func(w http.ResponseWriter) {
+		w.Header().Set("X-RateLimit-Reset", "0.0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	/* Act */
+	resp := readDeletedRegistryProvider(t, retryProviderValues(server.URL, 2))
+
+	/* Assert */
+	assert.True(t, resp.Diagnostics.HasError())
+	assert.Contains(t, resp.Diagnostics.Errors()[0].Detail(), "429")
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func Test_provider_honors_rate_limit_reset(t *testing.T) {
+	/* Arrange */
+	isolateCliConfig(t)
+	server, _ := newThrottlingApiServer(t, 1, func(w http.ResponseWriter) {
+		w.Header().Set("X-RateLimit-Reset", "0.2")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	providerValues := retryProviderValues(server.URL, 5)
+	providerValues["retry_wait_max"] = tftypes.NewValue(tftypes.Number, 1)
+	start := time.Now()
+
+	/* Act */
+	assertAuthenticated(t, providerValues)
+
+	/* Assert */
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+}
+
+func Test_provider_honors_retry_after(t *testing.T) {
+	/* Arrange */
+	isolateCliConfig(t)
+	server, _ := newThrottlingApiServer(t, 1, func(w http.ResponseWriter) {
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	providerValues := retryProviderValues(server.URL, 5)
+	providerValues["retry_wait_max"] = tftypes.NewValue(tftypes.Number, 1)
+	start := time.Now()
+
+	/* Act */
+	assertAuthenticated(t, providerValues)
+
+	/* Assert */
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+}
+
+func Test_provider_caps_server_wait(t *testing.T) {
+	/* Arrange */
+	isolateCliConfig(t)
+	server, requests := newThrottlingApiServer(t, 1, func(w http.ResponseWriter) {
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	start := time.Now()
+
+	/* Act */
+	assertAuthenticated(t, retryProviderValues(server.URL, 5))
+
+	/* Assert */
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func Test_provider_gives_up_on_wait_past_deadline(t *testing.T) {
+	/* Arrange */
+	isolateCliConfig(t)
+	server, requests := newThrottlingApiServer(t, 1, func(w http.ResponseWriter) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	providerValues := retryProviderValues(server.URL, 5)
+	providerValues["retry_wait_max"] = tftypes.NewValue(tftypes.Number, 60)
+	r := newTestResource(t, "tfepatch_registry_provider", providerValues)
+	state := newTestState(t, r, &m.RegistryProvider{
+		Id:           types.StringValue("test-org||demo-provider||private"),
+		Organization: types.StringValue("test-org"),
+		Namespace:    types.StringValue("test-org"),
+		Name:         types.StringValue("demo-provider"),
+		RegistryName: types.StringValue("private"),
+	})
+	resp := fwresource.ReadResponse{State: state}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	start := time.Now()
+
+	/* Act */
+	r.Read(ctx, fwresource.ReadRequest{State: state}, &resp)
+
+	/* Assert */
+	assert.True(t, resp.Diagnostics.HasError())
+	assert.Contains(t, resp.Diagnostics.Errors()[0].Detail(), "429")
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func Test_provider_does_not_retry_failed_create(t *testing.T) {
+	/* Arrange */
+	isolateCliConfig(t)
+	var requests atomic.Int32
+	server := newTestApiServer(t, func(w http.ResponseWriter, r *http.Request) {
+		// A throttled create is retried as it was never processed, a failed create is not as it may have been
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	r := newTestResource(t, "tfepatch_registry_provider", retryProviderValues(server.URL, 5))
+	planReq := newTestModifyPlanRequest(t, r, map[string]tftypes.Value{
+		"organization":  tftypes.NewValue(tftypes.String, "test-org"),
+		"namespace":     tftypes.NewValue(tftypes.String, "test-org"),
+		"name":          tftypes.NewValue(tftypes.String, "demo-provider"),
+		"registry_name": tftypes.NewValue(tftypes.String, "private"),
+	}, nil)
+	resp := fwresource.CreateResponse{State: tfsdk.State{Schema: planReq.State.Schema, Raw: planReq.State.Raw}}
+
+	/* Act */
+	r.Create(context.Background(), fwresource.CreateRequest{Config: planReq.Config, Plan: planReq.Plan}, &resp)
+
+	/* Assert */
+	assert.True(t, resp.Diagnostics.HasError())
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+// newThrottlingApiServer starts a test server that fails the first requests with the throttle response and then answers as if the requested object is deleted.
+func newThrottlingApiServer(t *testing.T, throttled int32, throttle func(w http.ResponseWriter)) (*httptest.Server, *atomic.Int32) {
+	requests := &atomic.Int32{}
+	server := newTestApiServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= throttled {
+			throttle(w)
+			return
+		}
+		tokenHandler("test-token")(w, r)
+	})
+	return server, requests
+}
+
+// retryProviderValues returns the provider configuration for a test server with retries that do not wait unless told to by the server.
+func retryProviderValues(hostname string, maxRetries int64) map[string]tftypes.Value {
+	values := testProviderValues(hostname)
+	values["max_retries"] = tftypes.NewValue(tftypes.Number, maxRetries)
+	values["retry_wait_min"] = tftypes.NewValue(tftypes.Number, 0)
+	values["retry_wait_max"] = tftypes.NewValue(tftypes.Number, 0)
+	return values
+}
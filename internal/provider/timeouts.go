@@ -0,0 +1,28 @@
+package provider
+
+import (
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+)
+
+// The timeouts of the resource operations when the timeouts block does not set them.
+// Creates and updates are the longest as they upload the SHA256SUMS files and provider archives.
+const (
+	defaultCreateTimeout = 20 * time.Minute
+	defaultReadTimeout   = 5 * time.Minute
+	defaultUpdateTimeout = 20 * time.Minute
+	defaultDeleteTimeout = 10 * time.Minute
+)
+
+// timeoutsOpts are the options of the timeouts block of the resources, documenting the default of each operation.
+var timeoutsOpts = timeouts.Opts{
+	Create:            true,
+	Read:              true,
+	Update:            true,
+	Delete:            true,
+	CreateDescription: "The timeout for creating the resource, as a duration such as \"30s\" or \"2h45m\". Defaults to 20m",
+	ReadDescription:   "The timeout for reading the resource, as a duration such as \"30s\" or \"2h45m\". Defaults to 5m",
+	UpdateDescription: "The timeout for updating the resource, as a duration such as \"30s\" or \"2h45m\". Defaults to 20m",
+	DeleteDescription: "The timeout for deleting the resource, as a duration such as \"30s\" or \"2h45m\". Defaults to 10m",
+}
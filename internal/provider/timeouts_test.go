@@ -0,0 +1,95 @@
+package provider_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+// timeoutsValue returns the value of a timeouts block with the create timeout.
+func timeoutsValue(create string) tftypes.Value {
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"create": tftypes.String, "read": tftypes.String, "update": tftypes.String, "delete": tftypes.String}}
+	return tftypes.NewValue(typ, map[string]tftypes.Value{
+		"create": tftypes.NewValue(tftypes.String, create),
+		"read":   tftypes.NewValue(tftypes.String, nil),
+		"update": tftypes.NewValue(tftypes.String, nil),
+		"delete": tftypes.NewValue(tftypes.String, nil),
+	})
+}
+
+func Test_timeouts_bound_the_tfe_requests(t *testing.T) {
+	/* Arrange */
+	release := make(chan struct{})
+	server := newTestApiServer(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		notFoundHandler(w, r)
+	})
+	// Cleanups run last in first out, so the handler returns before the server closes
+	t.Cleanup(func() { close(release) })
+	r := newTestResource(t, "tfepatch_registry_provider", testProviderValues(server.URL))
+	planReq := newTestModifyPlanRequest(t, r, map[string]tftypes.Value{
+		"organization":  tftypes.NewValue(tftypes.String, "test-org"),
+		"namespace":     tftypes.NewValue(tftypes.String, "test-org"),
+		"name":          tftypes.NewValue(tftypes.String, "demo-provider"),
+		"registry_name": tftypes.NewValue(tftypes.String, "private"),
+		"timeouts":      timeoutsValue("100ms"),
+	}, nil)
+	resp := fwresource.CreateResponse{State: tfsdk.State{Schema: planReq.State.Schema, Raw: planReq.State.Raw}}
+	start := time.Now()
+
+	/* Act */
+	r.Create(context.Background(), fwresource.CreateRequest{Config: planReq.Config, Plan: planReq.Plan}, &resp)
+
+	/* Assert */
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, 1, resp.Diagnostics.ErrorsCount())
+	assert.Equal(t, "TFE request timed out", resp.Diagnostics.Errors()[0].Summary())
+	assert.Contains(t, resp.Diagnostics.Errors()[0].Detail(), "Could not create the registry provider before the timeout")
+}
+
+func Test_timeouts_must_be_durations(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout string
+		valid   bool
+	}{
+		{name: "minutes", timeout: "30m", valid: true},
+		{name: "compound", timeout: "2h45m", valid: true},
+		{name: "without unit", timeout: "30"},
+		{name: "not a duration", timeout: "soon"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* Arrange */
+			r := newTestResource(t, "tfepatch_gpg_key", testProviderValues("localhost"))
+			var schemaResp fwresource.SchemaResponse
+			r.Schema(context.Background(), fwresource.SchemaRequest{}, &schemaResp)
+			block := schemaResp.Schema.Blocks["timeouts"].(schema.SingleNestedBlock)
+
+			for _, operation := range []string{"create", "read", "update", "delete"} {
+				resp := &validator.StringResponse{}
+
+				/* Act */
+				for _, v := range block.Attributes[operation].(schema.StringAttribute).Validators {
+					v.ValidateString(context.Background(), validator.StringRequest{
+						Path:        path.Root("timeouts").AtName(operation),
+						ConfigValue: types.StringValue(tt.timeout),
+					}, resp)
+				}
+
+				/* Assert */
+				assert.Equal(t, !tt.valid, resp.Diagnostics.HasError(), operation)
+			}
+		})
+	}
+}
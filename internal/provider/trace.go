@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	u "github.com/tsanton/terraform-provider-tfepatch/utilities"
+)
+
+const (
+	// apiLogSubsystem traces the TFE API requests, its level is set with TF_LOG_PROVIDER_TFEPATCH_TFE_API
+	apiLogSubsystem = "tfe_api"
+	// clientLogSubsystem holds the logs of the TFE client, its level is set with TF_LOG_PROVIDER_TFEPATCH_TFE_CLIENT
+	clientLogSubsystem = "tfe_client"
+)
+
+// traceTransport logs every request and its response at TRACE, with the Authorization header and the token masked.
+type traceTransport struct {
+	next http.RoundTripper
+	// token is masked in the traces
+	token string
+}
+
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The subsystem is derived from the request so that the traces carry the fields of the RPC sending it, i.e. tf_req_id
+	ctx := u.NewLogSubsystem(req.Context(), apiLogSubsystem, t.token)
+	tflog.SubsystemTrace(ctx, apiLogSubsystem, "Sending TFE API request", map[string]interface{}{
+		"method":          req.Method,
+		"path":            req.URL.Path,
+		"request_headers": maskedHeaders(req.Header),
+	})
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	fields := map[string]interface{}{
+		"method":  req.Method,
+		"path":    req.URL.Path,
+		"latency": time.Since(start).String(),
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.SubsystemTrace(ctx, apiLogSubsystem, "TFE API request failed", fields)
+		return resp, err
+	}
+	fields["status"] = resp.StatusCode
+	tflog.SubsystemTrace(ctx, apiLogSubsystem, "Received TFE API response", fields)
+	return resp, err
+}
+
+// maskedHeaders flattens the request headers for logging, masking the credentials.
+func maskedHeaders(header http.Header) map[string]string {
+	masked := map[string]string{}
+	for name, values := range header {
+		if strings.EqualFold(name, "Authorization") || strings.EqualFold(name, "Proxy-Authorization") {
+			masked[name] = "***"
+			continue
+		}
+		masked[name] = strings.Join(values, ", ")
+	}
+	return masked
+}
@@ -0,0 +1,59 @@
+package provider_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"github.com/stretchr/testify/assert"
+	m "github.com/tsanton/terraform-provider-tfepatch/provider/models"
+)
+
+func Test_api_requests_are_traced(t *testing.T) {
+	/* Arrange */
+	isolateCliConfig(t)
+	server := newTestApiServer(t, notFoundHandler)
+	r := newTestResource(t, "tfepatch_registry_provider", testProviderValues(server.URL))
+	state := newTestState(t, r, &m.RegistryProvider{
+		Id:           types.StringValue("test-org||demo-provider||private"),
+		Organization: types.StringValue("test-org"),
+		Namespace:    types.StringValue("test-org"),
+		Name:         types.StringValue("demo-provider"),
+		RegistryName: types.StringValue("private"),
+	})
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	// The framework sets the fields of the RPC on the logger it passes to the resource
+	ctx = tflog.SetField(ctx, "tf_req_id", "test-request")
+
+	/* Act */
+	r.Read(ctx, fwresource.ReadRequest{State: state}, &fwresource.ReadResponse{State: state})
+
+	/* Assert */
+	assert.NotContains(t, output.String(), "test-token")
+	entries, err := tflogtest.MultilineJSONDecode(strings.NewReader(output.String()))
+	assert.Nil(t, err)
+	traced := map[string]map[string]interface{}{}
+	for _, entry := range entries {
+		if entry["@module"] == "provider.tfe_api" {
+			traced[entry["@message"].(string)] = entry
+		}
+	}
+	request, response := traced["Sending TFE API request"], traced["Received TFE API response"]
+	if assert.NotNil(t, request) && assert.NotNil(t, response) {
+		assert.Equal(t, "trace", request["@level"])
+		assert.Equal(t, http.MethodGet, request["method"])
+		assert.Equal(t, "/api/v2/organizations/test-org/registry-providers/private/test-org/demo-provider", request["path"])
+		assert.Equal(t, "***", request["request_headers"].(map[string]interface{})["Authorization"])
+		assert.Equal(t, float64(http.StatusNotFound), response["status"])
+		assert.NotEmpty(t, response["latency"])
+		assert.Equal(t, "test-request", request["tf_req_id"])
+		assert.Equal(t, "test-request", response["tf_req_id"])
+	}
+}
@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+)
+
+// transportConfig holds the resolved TLS and proxy settings of the provider.
+type transportConfig struct {
+	skipTlsVerify  bool
+	caCertFile     string
+	caCertPem      string
+	clientCertFile string
+	clientKeyFile  string
+	httpsProxy     string
+	// token is masked in the request traces
+	token        string
+	maxRetries   int
+	retryWaitMin time.Duration
+	retryWaitMax time.Duration
+}
+
+// newHttpClient returns the client used for all API requests and uploads.
+func newHttpClient(config transportConfig) (*http.Client, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: config.skipTlsVerify,
+	}
+
+	if config.caCertFile != "" || config.caCertPem != "" {
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if config.caCertFile != "" {
+			pem, err := os.ReadFile(config.caCertFile)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read ca_cert_file: %w", err)
+			}
+			if !rootCAs.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no PEM encoded certificates in ca_cert_file %s", config.caCertFile)
+			}
+		}
+		if config.caCertPem != "" && !rootCAs.AppendCertsFromPEM([]byte(config.caCertPem)) {
+			return nil, errors.New("no PEM encoded certificates in ca_cert_pem")
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	if config.clientCertFile != "" {
+		certificate, err := tls.LoadX509KeyPair(config.clientCertFile, config.clientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load the client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	transport := cleanhttp.DefaultPooledTransport()
+	transport.TLSClientConfig = tlsConfig
+	if config.httpsProxy != "" {
+		proxyUrl, err := url.Parse(config.httpsProxy)
+		if err != nil || proxyUrl.Host == "" {
+			return nil, fmt.Errorf("invalid https_proxy %s", config.httpsProxy)
+		}
+		transport.Proxy = http.ProxyURL(proxyUrl)
+	}
+
+	httpClient := cleanhttp.DefaultPooledClient()
+	httpClient.Transport = &apiErrorTransport{
+		next: &retryTransport{
+			next:       &traceTransport{next: transport, token: config.token},
+			maxRetries: config.maxRetries,
+			waitMin:    config.retryWaitMin,
+			waitMax:    config.retryWaitMax,
+		},
+	}
+	return httpClient, nil
+}
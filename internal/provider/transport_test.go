@@ -0,0 +1,185 @@
+package provider_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	provider "github.com/tsanton/terraform-provider-tfepatch/provider"
+)
+
+func Test_provider_tls_untrusted_certificate(t *testing.T) {
+	/* Arrange */
+	isolateCliConfig(t)
+	server := newTestTlsApiServer(t, tokenHandler("test-token"), nil)
+
+	/* Act */
+	resp := readDeletedRegistryProvider(t, testProviderValues(server.URL))
+
+	/* Assert */
+	assert.True(t, resp.Diagnostics.HasError())
+	assert.Contains(t, resp.Diagnostics.Errors()[0].Detail(), "certificate")
+}
+
+func Test_provider_tls_skip_verify(t *testing.T) {
+	/* Arrange */
+	isolateCliConfig(t)
+	server := newTestTlsApiServer(t, tokenHandler("test-token"), nil)
+	values := testProviderValues(server.URL)
+	values["ssl_skip_verify"] = tftypes.NewValue(tftypes.Bool, true)
+
+	/* Act & Assert */
+	assertAuthenticated(t, values)
+}
+
+func Test_provider_tls_skip_verify_false_verifies(t *testing.T) {
+	/* Arrange */
+	isolateCliConfig(t)
+	server := newTestTlsApiServer(t, tokenHandler("test-token"), nil)
+	values := testProviderValues(server.URL)
+	values["ssl_skip_verify"] = tftypes.NewValue(tftypes.Bool, false)
+
+	/* Act */
+	resp := readDeletedRegistryProvider(t, values)
+
+	/* Assert */
+	assert.True(t, resp.Diagnostics.HasError())
+}
+
+func Test_provider_tls_ca_cert_pem(t *testing.T) {
+	/* Arrange */
+	isolateCliConfig(t)
+	server := newTestTlsApiServer(t, tokenHandler("test-token"), nil)
+	values := testProviderValues(server.URL)
+	values["ca_cert_pem"] = tftypes.NewValue(tftypes.String, string(certificatePem(server.Certificate())))
+
+	/* Act & Assert */
+	assertAuthenticated(t, values)
+}
+
+func Test_provider_tls_ca_cert_file(t *testing.T) {
+	/* Arrange */
+	isolateCliConfig(t)
+	server := newTestTlsApiServer(t, tokenHandler("test-token"), nil)
+	caCertFile := filepath.Join(t.TempDir(), "ca.pem")
+	assert.Nil(t, os.WriteFile(caCertFile, certificatePem(server.Certificate()), 0o600))
+	values := testProviderValues(server.URL)
+	values["ca_cert_file"] = tftypes.NewValue(tftypes.String, caCertFile)
+
+	/* Act & Assert */
+	assertAuthenticated(t, values)
+}
+
+func Test_provider_tls_invalid_ca_cert_pem(t *testing.T) {
+	/* Arrange */
+	isolateCliConfig(t)
+	values := testProviderValues("tfe.example.com")
+	values["ca_cert_pem"] = tftypes.NewValue(tftypes.String, "not a certificate")
+
+	/* Act */
+	resp := configureTestProvider(provider.New()(), values)
+
+	/* Assert */
+	assert.True(t, resp.Diagnostics.HasError())
+	assert.Contains(t, resp.Diagnostics.Errors()[0].Detail(), "ca_cert_pem")
+}
+
+func Test_provider_tls_client_certificate(t *testing.T) {
+	/* Arrange */
+	isolateCliConfig(t)
+	clientCert, clientCertFile, clientKeyFile := generateClientCertificate(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	server := newTestTlsApiServer(t, tokenHandler("test-token"), clientCAs)
+	values := testProviderValues(server.URL)
+	values["ca_cert_pem"] = tftypes.NewValue(tftypes.String, string(certificatePem(server.Certificate())))
+	values["client_cert_file"] = tftypes.NewValue(tftypes.String, clientCertFile)
+	values["client_key_file"] = tftypes.NewValue(tftypes.String, clientKeyFile)
+
+	/* Act & Assert */
+	assertAuthenticated(t, values)
+}
+
+func Test_provider_tls_missing_client_certificate(t *testing.T) {
+	/* Arrange */
+	isolateCliConfig(t)
+	clientCert, _, _ := generateClientCertificate(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	server := newTestTlsApiServer(t, tokenHandler("test-token"), clientCAs)
+	values := testProviderValues(server.URL)
+	values["ca_cert_pem"] = tftypes.NewValue(tftypes.String, string(certificatePem(server.Certificate())))
+
+	/* Act */
+	resp := readDeletedRegistryProvider(t, values)
+
+	/* Assert */
+	assert.True(t, resp.Diagnostics.HasError())
+}
+
+func Test_provider_https_proxy(t *testing.T) {
+	/* Arrange */
+	isolateCliConfig(t)
+	proxied := false
+	proxy := newTestApiServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Host != "tfe.invalid" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		proxied = true
+		tokenHandler("test-token")(w, r)
+	})
+	values := testProviderValues("http://tfe.invalid")
+	values["https_proxy"] = tftypes.NewValue(tftypes.String, proxy.URL)
+
+	/* Act */
+	assertAuthenticated(t, values)
+
+	/* Assert */
+	assert.True(t, proxied)
+}
+
+func certificatePem(certificate *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw})
+}
+
+// generateClientCertificate writes a self-signed client certificate and its key to PEM files.
+func generateClientCertificate(t *testing.T) (*x509.Certificate, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "tfepatch"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.Nil(t, err)
+	certificate, err := x509.ParseCertificate(der)
+	assert.Nil(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.Nil(t, err)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client-key.pem")
+	assert.Nil(t, os.WriteFile(certFile, certificatePem(certificate), 0o600))
+	assert.Nil(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600))
+	return certificate, certFile, keyFile
+}
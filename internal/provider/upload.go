@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// uploadFile streams the content of a local file to an upload link returned by the registry API.
+// The upload links point to the TFE object storage (archivist) and are authenticated by the link itself.
+func uploadFile(ctx context.Context, client *http.Client, uploadUrl, filePath string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("unable to open file %s: %w", filePath, err)
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("unable to stat file %s: %w", filePath, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, uploadUrl, f)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = stat.Size()
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to upload file %s: %w", filePath, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("upload of file %s returned non 2xx response: %d", filePath, resp.StatusCode)
+	}
+	return nil
+}
+
+// fileSha256 returns the hex encoded sha256 checksum of a local file.
+func fileSha256(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", fmt.Errorf("unable to open file %s: %w", filePath, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("unable to read file %s: %w", filePath, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
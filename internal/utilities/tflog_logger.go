@@ -0,0 +1,64 @@
+package utilities
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// LogLevelEnvPrefix prefixes the environment variables setting the log level of a subsystem, e.g. TF_LOG_PROVIDER_TFEPATCH_TFE_CLIENT
+const LogLevelEnvPrefix = "TF_LOG_PROVIDER_TFEPATCH"
+
+// TflogLogger implements ILogger by logging to a tflog subsystem, so that the output honors TF_LOG and the plugin log levels.
+type TflogLogger struct {
+	ctx       context.Context
+	subsystem string
+}
+
+// NewTflogLogger returns a logger for the subsystem of the logger in ctx. The masked strings are replaced in messages and fields.
+func NewTflogLogger(ctx context.Context, subsystem string, masked ...string) *TflogLogger {
+	return &TflogLogger{ctx: NewLogSubsystem(ctx, subsystem, masked...), subsystem: subsystem}
+}
+
+// Detach stops logging through the context of the logger once the RPC it belongs to has completed. Later messages are dropped
+// rather than logged with the fields of a finished RPC.
+func (l *TflogLogger) Detach() {
+	l.ctx = context.Background()
+}
+
+// NewLogSubsystem adds the subsystem to the logger in ctx, at the level of its environment variable, masking the non-empty masked strings.
+// The subsystem logs the fields of the root logger, i.e. the tf_req_id and tf_resource_type of the RPC.
+func NewLogSubsystem(ctx context.Context, subsystem string, masked ...string) context.Context {
+	ctx = tflog.NewSubsystem(ctx, subsystem, tflog.WithLevelFromEnv(LogLevelEnvPrefix, subsystem), tflog.WithRootFields())
+	for _, value := range masked {
+		if value != "" {
+			ctx = tflog.SubsystemMaskLogStrings(ctx, subsystem, value)
+		}
+	}
+	return ctx
+}
+
+func (l *TflogLogger) Debug(args ...interface{}) {
+	tflog.SubsystemDebug(l.ctx, l.subsystem, fmt.Sprint(args...))
+}
+
+func (l *TflogLogger) Info(args ...interface{}) {
+	tflog.SubsystemInfo(l.ctx, l.subsystem, fmt.Sprint(args...))
+}
+
+func (l *TflogLogger) Error(args ...interface{}) {
+	tflog.SubsystemError(l.ctx, l.subsystem, fmt.Sprint(args...))
+}
+
+func (l *TflogLogger) Debugf(msg string, args ...interface{}) {
+	tflog.SubsystemDebug(l.ctx, l.subsystem, fmt.Sprintf(msg, args...))
+}
+
+func (l *TflogLogger) Infof(msg string, args ...interface{}) {
+	tflog.SubsystemInfo(l.ctx, l.subsystem, fmt.Sprintf(msg, args...))
+}
+
+func (l *TflogLogger) Errorf(msg string, args ...interface{}) {
+	tflog.SubsystemError(l.ctx, l.subsystem, fmt.Sprintf(msg, args...))
+}
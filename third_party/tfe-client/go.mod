@@ -0,0 +1,17 @@
+module github.com/tsanton/tfe-client
+
+go 1.19
+
+require (
+	github.com/hashicorp/go-cleanhttp v0.5.2
+	github.com/sirupsen/logrus v1.9.0
+	github.com/stretchr/testify v1.7.0
+	golang.org/x/crypto v0.8.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/sys v0.7.0 // indirect
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
+)
@@ -0,0 +1,14 @@
+package models
+
+import "net/http"
+
+type ClientConfig struct {
+	// The address of the Terraform Enterprise API.
+	Address string
+
+	// API token used to access the Terraform Enterprise API.
+	Token string
+
+	// The http client requests are sent with, i.e. to configure TLS, proxies or retries. Defaults to a pooled client.
+	HttpClient *http.Client
+}
@@ -0,0 +1,8 @@
+package enum
+
+type RegistryType string
+
+const (
+	RegistryTypePublic  RegistryType = "public"
+	RegistryTypePrivate RegistryType = "private"
+)
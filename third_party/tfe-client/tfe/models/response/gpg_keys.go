@@ -0,0 +1,7 @@
+package response
+
+type GpgKeys struct {
+	Data  []GpgKeyData `json:"data"`
+	Links ListLinks
+	Meta  ListMeta
+}
@@ -0,0 +1,7 @@
+package response
+
+type ProviderVersionPlatforms struct {
+	Data  []ProviderVersionPlatformData `json:"data"`
+	Links ListLinks
+	Meta  ListMeta
+}
@@ -0,0 +1,7 @@
+package response
+
+type ProviderVersions struct {
+	Data  []ProviderVersionData `json:"data"`
+	Links ListLinks
+	Meta  ListMeta
+}